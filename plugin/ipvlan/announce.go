@@ -0,0 +1,137 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	announceCountOpt    = "announce_count"
+	announceIntervalOpt = "announce_interval"
+	// gratuitous ARPs and neighbor advertisements sent per address at join
+	defaultAnnounceCount    = 3
+	defaultAnnounceInterval = time.Second
+	maxAnnounceCount        = 100
+)
+
+// parseAnnounceOpts binds -o announce_count and -o announce_interval to a
+// network. The l2 modes announce by default, l3 links have no neighbors to
+// update.
+func parseAnnounceOpts(n *network, countOpt, intervalOpt string) error {
+	n.announceCount = defaultAnnounceCount
+	n.announceInterval = defaultAnnounceInterval
+	if !n.l2() {
+		n.announceCount = 0
+		return nil
+	}
+	if countOpt != "" {
+		count, err := strconv.Atoi(countOpt)
+		if err != nil || count < 0 || count > maxAnnounceCount {
+			return fmt.Errorf("invalid %s [ %s ], must be between 0 and %d", announceCountOpt, countOpt, maxAnnounceCount)
+		}
+		n.announceCount = count
+	}
+	if intervalOpt != "" {
+		interval, err := time.ParseDuration(intervalOpt)
+		if err != nil || interval < 10*time.Millisecond || interval > time.Minute {
+			return fmt.Errorf("invalid %s [ %s ], must be a duration between 10ms and 1m", announceIntervalOpt, intervalOpt)
+		}
+		n.announceInterval = interval
+	}
+	return nil
+}
+
+// announceEndpoint sends gratuitous ARPs and unsolicited neighbor
+// advertisements for the addresses of a joined endpoint from its sandbox,
+// so the neighbors drop the entries of a host the address moved from
+func announceEndpoint(n *network, eid string) {
+	ep := n.endpointCopy(eid)
+	if ep == nil || n.announceCount == 0 || (ep.addr == nil && ep.addrV6 == nil) {
+		return
+	}
+	var announcers []*announcer
+	onSandboxLink(ep, "address announcements", func(link netlink.Link) error {
+		// libnetwork brings the link up once the addresses are set
+		if link.Attrs().Flags&net.FlagUp == 0 {
+			return errSandboxPending
+		}
+		var err error
+		announcers, err = newAnnouncers(link, ep)
+		return err
+	})
+	// the sockets stay in the sandbox, the namespace is not held while waiting
+	defer func() {
+		for _, a := range announcers {
+			syscall.Close(a.fd)
+		}
+	}()
+	for i := 0; i < n.announceCount && len(announcers) > 0; i++ {
+		if i > 0 {
+			time.Sleep(n.announceInterval)
+		}
+		for _, a := range announcers {
+			if err := sendPacket(a.fd, a.ifIndex, a.proto, a.dst, a.pkt); err != nil {
+				log.Debugf("Unable to announce the addresses of endpoint [ %s ]: %s", eid, err)
+				return
+			}
+		}
+	}
+	if len(announcers) > 0 {
+		log.Debugf("Announced the addresses of endpoint [ %s ] [ %d ] times", eid, n.announceCount)
+	}
+}
+
+// announcer is a packet socket in a sandbox and the announcement it repeats
+type announcer struct {
+	fd      int
+	ifIndex int
+	proto   uint16
+	dst     net.HardwareAddr
+	pkt     []byte
+}
+
+// newAnnouncers opens the sockets announcing the endpoint addresses on the
+// sandbox link, it runs in the sandbox namespace
+func newAnnouncers(link netlink.Link, ep *endpoint) ([]*announcer, error) {
+	ifIndex := link.Attrs().Index
+	mac := link.Attrs().HardwareAddr
+	var announcers []*announcer
+	if ep.addr != nil {
+		fd, err := packetSocket(ifIndex, syscall.ETH_P_ARP, 0)
+		if err != nil {
+			return nil, err
+		}
+		// an ARP request for its own address updates the caches of every
+		// host that already holds an entry for it
+		announcers = append(announcers, &announcer{
+			fd:      fd,
+			ifIndex: ifIndex,
+			proto:   syscall.ETH_P_ARP,
+			dst:     broadcastMAC,
+			pkt:     arpPacket(arpRequest, mac, ep.addr.IP, make(net.HardwareAddr, 6), ep.addr.IP),
+		})
+	}
+	if ep.addrV6 != nil {
+		fd, err := packetSocket(ifIndex, ethPIPv6, 0)
+		if err != nil {
+			for _, a := range announcers {
+				syscall.Close(a.fd)
+			}
+			return nil, err
+		}
+		announcers = append(announcers, &announcer{
+			fd:      fd,
+			ifIndex: ifIndex,
+			proto:   ethPIPv6,
+			dst:     multicastMAC(allNodes),
+			pkt:     ndpPacket(icmpv6NeighborAdvert, ep.addrV6.IP, allNodes, ep.addrV6.IP, ndpOverride, mac),
+		})
+	}
+	return announcers, nil
+}
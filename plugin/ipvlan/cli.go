@@ -1,25 +1,51 @@
 package ipvlan
 
-import "github.com/codegangsta/cli"
+import (
+	"os"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/codegangsta/cli"
+)
 
 var (
 	// Exported user CLI flag config options
 	// Most of these are depricated with libnetwork now accepting --options
-	FlagIPVlanMode     = cli.StringFlag{Name: "mode", Value: ipVlanMode, Usage: "name of the ipvlan mode [l2|l3]. (default: l2)"}
+	FlagIPVlanMode     = cli.StringFlag{Name: "mode", Value: ipVlanMode, Usage: "name of the ipvlan mode [l2|l3|l3s|l3routing]. (default: l2)"}
 	FlagGateway        = cli.StringFlag{Name: "gateway", Value: "", Usage: "IP of the default gateway (defaultL2 mode: first usable address of a subnet. Subnet 192.168.1.0/24 would mean the container gateway to 192.168.1.1)"}
 	FlagSubnet         = cli.StringFlag{Name: "ipvlan-subnet", Value: defaultSubnet, Usage: "subnet for the containers (l2 mode: 192.168.1.0/24)"}
-	FlagMtu            = cli.IntFlag{Name: "mtu", Value: cliMTU, Usage: "MTU of the container interface (default: 1500)"}
-	FlagIpvlanEthIface = cli.StringFlag{Name: "host-interface", Value: ipVlanEthIface, Usage: "(required) interface that the container will be communicating outside of the docker host with"}
+	FlagMtu            = cli.IntFlag{Name: "mtu", Value: cliMTU, Usage: "MTU of the container interface (default: the MTU of the parent interface)"}
+	FlagTxQueueLen     = cli.IntFlag{Name: "txqueuelen", Value: cliTxQueueLen, Usage: "transmit queue length of the container interface (default: 0)"}
+	FlagIpvlanEthIface = cli.StringFlag{Name: "host-interface", Value: ipVlanEthIface, Usage: "(required) interface that the container will be communicating outside of the docker host with, auto selects the default route interface"}
 	FlagRoutingManager = cli.StringFlag{Name: "routemng", Value: routingManager, Usage: "name of the routing manager name [gobgp]. (default: gobgp)"}
 	FlagBgpAs          = cli.StringFlag{Name: "as", Value: BgpAs, Usage: "AS number of bgp router. (default: 65000)"}
+	FlagStateDir       = cli.StringFlag{Name: "state-dir", Value: stateDir, Usage: "directory the network and endpoint state is persisted to. (default: /var/lib/ipvlan-docker-plugin)"}
+	FlagGcInterval     = cli.DurationFlag{Name: "gc-interval", Value: gcInterval, Usage: "interval between orphaned link and netns collections, 0 disables the collector. (default: 5m)"}
+	FlagGcDelete       = cli.BoolFlag{Name: "gc-delete", Usage: "delete the orphaned links and netns the collector finds, they are only reported by default"}
+	FlagMacvlanSocket  = cli.StringFlag{Name: "macvlan-socket", Value: "", Usage: "also serve a macvlan driver on this unix socket, e.g. macvlan.sock. (default: disabled)"}
 )
 
+// CmdOptions prints the docker network and endpoint options the driver accepts
+var CmdOptions = cli.Command{
+	Name:  "options",
+	Usage: "print the supported network and endpoint -o options and the rules between them",
+	Flags: []cli.Flag{cli.BoolFlag{Name: "json", Usage: "print the options as JSON"}},
+	Action: func(ctx *cli.Context) {
+		if err := printOptions(os.Stdout, ctx.Bool("json")); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
 var (
 	// These are the default values that are overwritten if flags are used at runtime
 	ipVlanMode     = "l2"             // ipvlan l2 is the default
 	ipVlanEthIface = "eth1"           // default to eth0?
 	defaultSubnet  = "192.168.1.0/24" // Should this just be the eth0 IP subnet?
-	cliMTU         = 1500
+	cliMTU         = 0
+	cliTxQueueLen  = 0
 	routingManager = "gobgp"
 	BgpAs          = "65000"
+	stateDir       = "/var/lib/ipvlan-docker-plugin"
+	gcInterval     = 5 * time.Minute
 )
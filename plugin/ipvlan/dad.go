@@ -0,0 +1,172 @@
+package ipvlan
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	dadOpt = "dad"
+
+	dadOff     = "off"
+	dadWarn    = "warn"
+	dadEnforce = "enforce"
+
+	// probes sent per address and the time left for an answer after the
+	// last one, shorter than the RFC 5227 timers to keep container starts fast
+	dadProbes   = 3
+	dadInterval = 200 * time.Millisecond
+	dadWait     = time.Second
+)
+
+// parseDadOpt binds -o dad to a network, duplicate address detection only
+// applies to the l2 modes where the endpoints share the parent segment
+func parseDadOpt(n *network, opt string) error {
+	switch opt {
+	case "", dadOff:
+		n.dad = ""
+		return nil
+	case dadWarn, dadEnforce:
+	default:
+		return fmt.Errorf("invalid dad [ %s ], valid values are [ %s | %s | %s ]", opt, dadOff, dadWarn, dadEnforce)
+	}
+	if !n.l2() {
+		return fmt.Errorf("dad requires an l2 or macvlan network, l3 endpoints are not on the parent segment")
+	}
+	n.dad = opt
+	return nil
+}
+
+// detectDuplicates probes the parent segment for other owners of the
+// endpoint addresses. A conflict fails an enforcing network and is only
+// logged by a warning one, probes that cannot be sent never fail.
+func detectDuplicates(n *network, ep *endpoint) error {
+	if n.dad == "" {
+		return nil
+	}
+	parent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		log.Warnf("Skipping duplicate address detection, parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+		return nil
+	}
+	for _, addr := range []*net.IPNet{ep.addr, ep.addrV6} {
+		if addr == nil {
+			continue
+		}
+		owner, err := addrOwner(parent, addr.IP)
+		if err != nil {
+			log.Warnf("Unable to probe [ %s ] on [ %s ] for duplicates: %s", addr.IP, n.ifaceOpt, err)
+			continue
+		}
+		if owner == "" {
+			continue
+		}
+		err = fmt.Errorf("address [ %s ] of endpoint [ %s ] is already used on [ %s ] by [ %s ]", addr.IP, ep.id, n.ifaceOpt, owner)
+		if n.dad == dadWarn {
+			log.Warnf("%s", err)
+			continue
+		}
+		return err
+	}
+	return nil
+}
+
+// addrOwner returns who answers for an address, the host itself or the mac
+// of a node on the parent segment, empty when the address is free
+func addrOwner(parent netlink.Link, ip net.IP) (string, error) {
+	// the host stack never sees the probes sent on the parent
+	local, _ := netlink.AddrList(nil, netlink.FAMILY_ALL)
+	for _, addr := range local {
+		if addr.IP.Equal(ip) {
+			return "this host", nil
+		}
+	}
+	var mac net.HardwareAddr
+	var err error
+	if ip.To4() != nil {
+		mac, err = arpProbe(parent, ip)
+	} else {
+		mac, err = dadSolicit(parent, ip)
+	}
+	if err != nil || mac == nil {
+		return "", err
+	}
+	return mac.String(), nil
+}
+
+// arpProbe sends RFC 5227 probes for an IPv4 address. Any ARP message with
+// the address as its sender, or a probe for it from another node, is a
+// conflict.
+func arpProbe(parent netlink.Link, ip net.IP) (net.HardwareAddr, error) {
+	ifIndex := parent.Attrs().Index
+	fd, err := packetSocket(ifIndex, syscall.ETH_P_ARP, dadInterval/2)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+	sha := parent.Attrs().HardwareAddr
+	probe := arpPacket(arpRequest, sha, net.IPv4zero, make(net.HardwareAddr, 6), ip)
+	return probeLoop(fd, func() error {
+		return sendPacket(fd, ifIndex, syscall.ETH_P_ARP, broadcastMAC, probe)
+	}, func(pkt []byte) bool {
+		if len(pkt) < 28 {
+			return false
+		}
+		spa, tpa := net.IP(pkt[14:18]), net.IP(pkt[24:28])
+		return spa.Equal(ip) || (spa.Equal(net.IPv4zero) && tpa.Equal(ip) && !bytes.Equal(pkt[8:14], sha))
+	})
+}
+
+// dadSolicit sends IPv6 duplicate address detection solicitations from the
+// unspecified address. An advertisement of the address, or a solicitation
+// of another node detecting it at the same time, is a conflict.
+func dadSolicit(parent netlink.Link, ip net.IP) (net.HardwareAddr, error) {
+	ifIndex := parent.Attrs().Index
+	fd, err := packetSocket(ifIndex, ethPIPv6, dadInterval/2)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd)
+	group := solicitedNode(ip)
+	ns := ndpPacket(icmpv6NeighborSolicit, net.IPv6unspecified, group, ip, 0, nil)
+	return probeLoop(fd, func() error {
+		return sendPacket(fd, ifIndex, ethPIPv6, multicastMAC(group), ns)
+	}, func(pkt []byte) bool {
+		t, src, target := parseNdp(pkt)
+		if target == nil || !target.Equal(ip) {
+			return false
+		}
+		return t == icmpv6NeighborAdvert || src.Equal(net.IPv6unspecified)
+	})
+}
+
+// probeLoop sends the probes and watches the replies for a conflict, it
+// returns the mac a conflicting packet came from
+func probeLoop(fd int, send func() error, conflict func(pkt []byte) bool) (net.HardwareAddr, error) {
+	buf := make([]byte, 65536)
+	for probe := 0; probe < dadProbes; probe++ {
+		if err := send(); err != nil {
+			return nil, err
+		}
+		wait := dadInterval
+		if probe == dadProbes-1 {
+			wait = dadWait
+		}
+		for deadline := time.Now().Add(wait); time.Now().Before(deadline); {
+			n, mac, err := recvPacket(fd, buf)
+			if err != nil {
+				return nil, err
+			}
+			if n > 0 && conflict(buf[:n]) {
+				return mac, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,450 @@
+package ipvlan
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	dhcpOpt = "dhcp"
+
+	dhcpServerPort = 67
+	dhcpClientPort = 68
+	// time a reply is waited for and the number of times a request is sent
+	dhcpTimeout = 2 * time.Second
+	dhcpRetries = 3
+	// how often the leases are checked for renewal
+	dhcpRenewCheck = 30 * time.Second
+
+	dhcpDiscover = 1
+	dhcpOffer    = 2
+	dhcpRequest  = 3
+	dhcpAck      = 5
+	dhcpNak      = 6
+	dhcpRelease  = 7
+
+	optSubnetMask    = 1
+	optRouter        = 3
+	optRequestedIP   = 50
+	optLeaseTime     = 51
+	optMessageType   = 53
+	optServerID      = 54
+	optParamRequest  = 55
+	optRenewalTime   = 58
+	optRebindingTime = 59
+	optClientID      = 61
+	optEnd           = 255
+)
+
+var dhcpMagic = []byte{99, 130, 83, 99}
+
+// dhcpLease is the DHCP lease of an endpoint address
+type dhcpLease struct {
+	ClientID  string
+	Addr      string
+	Router    string
+	Server    string
+	ServerMAC string
+	Obtained  time.Time
+	Duration  time.Duration
+	Renew     time.Duration
+}
+
+func (l *dhcpLease) ipNet() *net.IPNet {
+	ipNet, _ := netlink.ParseIPNet(l.Addr)
+	return ipNet
+}
+
+// due reports whether the lease has reached its renewal time
+func (l *dhcpLease) due(now time.Time) bool {
+	return now.After(l.Obtained.Add(l.Renew))
+}
+
+func (l *dhcpLease) expired(now time.Time) bool {
+	return now.After(l.Obtained.Add(l.Duration))
+}
+
+// parseDhcpOpt binds -o dhcp to a network. The addresses of a DHCP network
+// come from the LAN server, so the network must use the null ipam driver
+// and only the subnet of the parent interface is known up front.
+func parseDhcpOpt(n *network, opt string) error {
+	if opt == "" {
+		return nil
+	}
+	dhcp, err := strconv.ParseBool(opt)
+	if err != nil {
+		return fmt.Errorf("invalid dhcp [ %s ], must be true or false", opt)
+	}
+	if !dhcp {
+		return nil
+	}
+	if !n.l2() {
+		return fmt.Errorf("dhcp requires an l2 or macvlan network, the containers must share the parent LAN")
+	}
+	if n.cidr != nil {
+		if ones, _ := n.cidr.Mask.Size(); ones > 0 {
+			return fmt.Errorf("dhcp networks lease their addresses from the LAN, create them with --ipam-driver=null")
+		}
+	}
+	n.dhcp = true
+	n.cidr = nil
+	addrs, _ := ifaceAddrs(n.ifaceOpt, netlink.FAMILY_V4)
+	for _, addr := range addrs {
+		n.cidr = &net.IPNet{IP: addr.IP.Mask(addr.Mask), Mask: addr.Mask}
+		break
+	}
+	return nil
+}
+
+// dhcpMac returns the mac of a DHCP endpoint, derived from the endpoint id
+// since the address is only known once the lease is acquired
+func dhcpMac(eid string) string {
+	sum := sha1.Sum([]byte(eid))
+	return makeMac(net.IP(sum[:4]))
+}
+
+// dhcpChaddr returns the client hardware address of an endpoint, ipvlan
+// children share the mac of the parent
+func dhcpChaddr(n *network, ep *endpoint, parent netlink.Link) net.HardwareAddr {
+	if n.macvlan() && ep.mac != nil {
+		return ep.mac
+	}
+	return parent.Attrs().HardwareAddr
+}
+
+// acquireLease leases an address for an endpoint on the network parent. A
+// requested address renews an existing lease.
+func acquireLease(n *network, ep *endpoint, requested net.IP) (*dhcpLease, error) {
+	parent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		return nil, fmt.Errorf("parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+	}
+	conn, err := openDhcpConn(parent.Attrs().Index)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open a DHCP socket on [ %s ]: %s", n.ifaceOpt, err)
+	}
+	defer conn.close()
+	msg := &dhcpMessage{
+		xid:      newXid(),
+		chaddr:   dhcpChaddr(n, ep, parent),
+		clientID: ep.id,
+		options:  map[byte][]byte{},
+	}
+	var server net.IP
+	if requested == nil {
+		msg.msgType = dhcpDiscover
+		offer, _, err := conn.exchange(msg, dhcpOffer)
+		if err != nil {
+			return nil, fmt.Errorf("no DHCP offer on [ %s ]: %s", n.ifaceOpt, err)
+		}
+		requested = offer.yiaddr
+		server = net.IP(offer.options[optServerID])
+	}
+	msg.msgType = dhcpRequest
+	msg.options[optRequestedIP] = requested.To4()
+	if server != nil {
+		msg.options[optServerID] = server.To4()
+	}
+	ack, serverMAC, err := conn.exchange(msg, dhcpAck)
+	if err != nil {
+		return nil, fmt.Errorf("DHCP request for [ %s ] on [ %s ] failed: %s", requested, n.ifaceOpt, err)
+	}
+	return newLease(ep.id, ack, serverMAC), nil
+}
+
+// newLease builds a lease from a DHCP ack
+func newLease(clientID string, ack *dhcpMessage, serverMAC net.HardwareAddr) *dhcpLease {
+	mask := net.IPMask(ack.options[optSubnetMask])
+	if len(mask) != net.IPv4len {
+		mask = ack.yiaddr.DefaultMask()
+	}
+	l := &dhcpLease{
+		ClientID:  clientID,
+		Addr:      (&net.IPNet{IP: ack.yiaddr, Mask: mask}).String(),
+		Server:    net.IP(ack.options[optServerID]).String(),
+		ServerMAC: serverMAC.String(),
+		Obtained:  time.Now(),
+		Duration:  optDuration(ack.options[optLeaseTime], time.Hour),
+	}
+	if router := ack.options[optRouter]; len(router) >= net.IPv4len {
+		l.Router = net.IP(router[:net.IPv4len]).String()
+	}
+	l.Renew = optDuration(ack.options[optRenewalTime], l.Duration/2)
+	return l
+}
+
+// optDuration decodes a DHCP time option in seconds
+func optDuration(b []byte, def time.Duration) time.Duration {
+	if len(b) != 4 {
+		return def
+	}
+	secs := binary.BigEndian.Uint32(b)
+	// an infinite lease is renewed daily
+	if secs == 0xffffffff {
+		return 24 * time.Hour
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// releaseLease gives an endpoint address back to the DHCP server
+func releaseLease(n *network, ep *endpoint) error {
+	if ep.lease == nil {
+		return nil
+	}
+	ipNet := ep.lease.ipNet()
+	server := net.ParseIP(ep.lease.Server)
+	if ipNet == nil || server == nil {
+		return fmt.Errorf("invalid lease [ %+v ]", ep.lease)
+	}
+	parent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		return fmt.Errorf("parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+	}
+	conn, err := openDhcpConn(parent.Attrs().Index)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+	msg := &dhcpMessage{
+		msgType:  dhcpRelease,
+		xid:      newXid(),
+		ciaddr:   ipNet.IP,
+		chaddr:   dhcpChaddr(n, ep, parent),
+		clientID: ep.lease.ClientID,
+		options:  map[byte][]byte{optServerID: server.To4()},
+	}
+	dstMAC, err := net.ParseMAC(ep.lease.ServerMAC)
+	if err != nil {
+		dstMAC = broadcastMAC
+	}
+	log.Infof("Releasing the DHCP lease of [ %s ] for endpoint [ %s ]", ipNet.IP, ep.id)
+	return conn.send(msg.marshal(), ipNet.IP, server, dstMAC)
+}
+
+// startLeaseRenewal starts renewing the leases once the first dhcp network
+// is created or restored
+func (driver *driver) startLeaseRenewal() {
+	driver.renewOnce.Do(func() {
+		go driver.renewLeases()
+	})
+}
+
+// renewLeases renews the DHCP leases of the endpoints when they reach their
+// renewal time. A lease that cannot be renewed is retried until it expires.
+func (driver *driver) renewLeases() {
+	for range time.Tick(dhcpRenewCheck) {
+		now := time.Now()
+		for _, n := range driver.getNetworks() {
+			if !n.dhcp {
+				continue
+			}
+			for _, ep := range n.endpointCopies() {
+				if ep.lease == nil || !ep.lease.due(now) {
+					continue
+				}
+				lease, err := acquireLease(n, ep, ep.lease.ipNet().IP)
+				if err != nil {
+					if ep.lease.expired(now) {
+						log.Errorf("The DHCP lease of [ %s ] for endpoint [ %s ] expired: %s", ep.lease.Addr, ep.id, err)
+					} else {
+						log.Warnf("Unable to renew the DHCP lease of [ %s ] for endpoint [ %s ]: %s", ep.lease.Addr, ep.id, err)
+					}
+					continue
+				}
+				if lease.Addr != ep.lease.Addr {
+					log.Errorf("The DHCP server renewed endpoint [ %s ] with [ %s ] instead of [ %s ]", ep.id, lease.Addr, ep.lease.Addr)
+				}
+				n.setEndpointLease(ep.id, lease)
+				log.Debugf("Renewed the DHCP lease of [ %s ] for endpoint [ %s ] for [ %s ]", lease.Addr, ep.id, lease.Duration)
+				if err := driver.saveState(); err != nil {
+					log.Warnf("Unable to persist the DHCP lease of endpoint [ %s ]: %s", ep.id, err)
+				}
+			}
+		}
+	}
+}
+
+// dhcpMessage is the subset of a BOOTP message the client uses
+type dhcpMessage struct {
+	op       byte
+	msgType  byte
+	xid      uint32
+	ciaddr   net.IP
+	yiaddr   net.IP
+	chaddr   net.HardwareAddr
+	clientID string
+	options  map[byte][]byte
+}
+
+// marshal encodes a client message with the broadcast flag so replies
+// reach the parent before the address is configured
+func (m *dhcpMessage) marshal() []byte {
+	b := make([]byte, 236, 300)
+	b[0] = 1 // BOOTREQUEST
+	b[1] = 1 // ethernet
+	b[2] = 6
+	binary.BigEndian.PutUint32(b[4:], m.xid)
+	binary.BigEndian.PutUint16(b[10:], 0x8000)
+	if m.ciaddr != nil {
+		copy(b[12:16], m.ciaddr.To4())
+	}
+	copy(b[28:44], m.chaddr)
+	b = append(b, dhcpMagic...)
+	b = append(b, optMessageType, 1, m.msgType)
+	if m.clientID != "" {
+		// type 0 marks an identifier that is not a hardware address
+		b = append(b, optClientID, byte(len(m.clientID)+1), 0)
+		b = append(b, m.clientID...)
+	}
+	for _, code := range []byte{optRequestedIP, optServerID} {
+		if v, ok := m.options[code]; ok {
+			b = append(b, code, byte(len(v)))
+			b = append(b, v...)
+		}
+	}
+	if m.msgType != dhcpRelease {
+		b = append(b, optParamRequest, 5, optSubnetMask, optRouter, optLeaseTime, optRenewalTime, optRebindingTime)
+	}
+	b = append(b, optEnd)
+	// some servers ignore messages shorter than a BOOTP message
+	for len(b) < 300 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// parseDhcpMessage decodes a server reply
+func parseDhcpMessage(b []byte) (*dhcpMessage, error) {
+	if len(b) < 240 || string(b[236:240]) != string(dhcpMagic) {
+		return nil, fmt.Errorf("not a DHCP message")
+	}
+	m := &dhcpMessage{
+		op:      b[0],
+		xid:     binary.BigEndian.Uint32(b[4:]),
+		yiaddr:  net.IP(append([]byte(nil), b[16:20]...)),
+		options: map[byte][]byte{},
+	}
+	for i := 240; i < len(b); {
+		code := b[i]
+		if code == optEnd {
+			break
+		}
+		if code == 0 {
+			i++
+			continue
+		}
+		if i+1 >= len(b) || i+2+int(b[i+1]) > len(b) {
+			return nil, fmt.Errorf("truncated DHCP option [ %d ]", code)
+		}
+		m.options[code] = b[i+2 : i+2+int(b[i+1])]
+		i += 2 + int(b[i+1])
+	}
+	if t := m.options[optMessageType]; len(t) == 1 {
+		m.msgType = t[0]
+	}
+	return m, nil
+}
+
+func newXid() uint32 {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return binary.BigEndian.Uint32(b)
+}
+
+// dhcpConn is a packet socket on the parent interface. It works before the
+// endpoint has an address and sees replies ipvlan would hand to a child.
+type dhcpConn struct {
+	fd      int
+	ifIndex int
+}
+
+func openDhcpConn(ifIndex int) (*dhcpConn, error) {
+	fd, err := packetSocket(ifIndex, syscall.ETH_P_IP, dhcpTimeout/4)
+	if err != nil {
+		return nil, err
+	}
+	return &dhcpConn{fd: fd, ifIndex: ifIndex}, nil
+}
+
+func (c *dhcpConn) close() {
+	syscall.Close(c.fd)
+}
+
+// send wraps a DHCP message in UDP and IPv4 headers, the kernel adds the
+// ethernet header for the destination mac
+func (c *dhcpConn) send(payload []byte, src, dst net.IP, dstMAC net.HardwareAddr) error {
+	pkt := make([]byte, 28+len(payload))
+	pkt[0] = 0x45
+	binary.BigEndian.PutUint16(pkt[2:], uint16(len(pkt)))
+	pkt[8] = 64
+	pkt[9] = syscall.IPPROTO_UDP
+	copy(pkt[12:16], src.To4())
+	copy(pkt[16:20], dst.To4())
+	binary.BigEndian.PutUint16(pkt[10:], checksum(pkt[:20]))
+	binary.BigEndian.PutUint16(pkt[20:], dhcpClientPort)
+	binary.BigEndian.PutUint16(pkt[22:], dhcpServerPort)
+	binary.BigEndian.PutUint16(pkt[24:], uint16(8+len(payload)))
+	// a zero UDP checksum is valid over IPv4
+	copy(pkt[28:], payload)
+	return sendPacket(c.fd, c.ifIndex, syscall.ETH_P_IP, dstMAC, pkt)
+}
+
+// exchange broadcasts a message until a reply of the wanted type arrives, a
+// NAK fails the exchange. It returns the reply and the mac it came from.
+func (c *dhcpConn) exchange(m *dhcpMessage, want byte) (*dhcpMessage, net.HardwareAddr, error) {
+	payload := m.marshal()
+	buf := make([]byte, 65536)
+	for attempt := 0; attempt < dhcpRetries; attempt++ {
+		if err := c.send(payload, net.IPv4zero, net.IPv4bcast, broadcastMAC); err != nil {
+			return nil, nil, err
+		}
+		deadline := time.Now().Add(dhcpTimeout)
+		for time.Now().Before(deadline) {
+			n, mac, err := recvPacket(c.fd, buf)
+			if err != nil {
+				return nil, nil, err
+			}
+			reply := parseReply(buf[:n])
+			if reply == nil || reply.op != 2 || reply.xid != m.xid {
+				continue
+			}
+			if reply.msgType == dhcpNak {
+				return nil, nil, fmt.Errorf("the DHCP server declined the request")
+			}
+			if reply.msgType != want {
+				continue
+			}
+			return reply, mac, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("no reply after [ %d ] attempts", dhcpRetries)
+}
+
+// parseReply returns the DHCP message of an IPv4 packet to the client port
+func parseReply(pkt []byte) *dhcpMessage {
+	if len(pkt) < 20 || pkt[0]>>4 != 4 || pkt[9] != syscall.IPPROTO_UDP {
+		return nil
+	}
+	ihl := int(pkt[0]&0x0f) * 4
+	if len(pkt) < ihl+8 || binary.BigEndian.Uint16(pkt[ihl+2:]) != dhcpClientPort {
+		return nil
+	}
+	end := ihl + int(binary.BigEndian.Uint16(pkt[ihl+4:]))
+	if end > len(pkt) || end < ihl+8 {
+		return nil
+	}
+	m, err := parseDhcpMessage(pkt[ihl+8 : end])
+	if err != nil {
+		return nil
+	}
+	return m
+}
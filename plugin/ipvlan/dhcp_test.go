@@ -0,0 +1,413 @@
+package ipvlan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+func seconds(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func TestDhcpMessageMarshal(t *testing.T) {
+	chaddr, _ := net.ParseMAC("7a:42:0a:00:00:05")
+	m := &dhcpMessage{
+		msgType:  dhcpRequest,
+		xid:      0xdeadbeef,
+		chaddr:   chaddr,
+		clientID: "ep1",
+		options: map[byte][]byte{
+			optRequestedIP: net.ParseIP("10.0.0.5").To4(),
+			optServerID:    net.ParseIP("10.0.0.1").To4(),
+		},
+	}
+	b := m.marshal()
+	if len(b) != 300 {
+		t.Fatalf("marshal() length = %d, want 300", len(b))
+	}
+	if b[0] != 1 || b[1] != 1 || b[2] != 6 {
+		t.Errorf("header = % x, want a BOOTREQUEST on ethernet", b[:3])
+	}
+	if flags := binary.BigEndian.Uint16(b[10:]); flags != 0x8000 {
+		t.Errorf("flags = %#x, want the broadcast flag", flags)
+	}
+	if !bytes.Equal(b[28:34], chaddr) {
+		t.Errorf("chaddr = % x, want %s", b[28:34], chaddr)
+	}
+	got, err := parseDhcpMessage(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.xid != m.xid || got.msgType != dhcpRequest {
+		t.Errorf("parsed xid %#x type %d, want %#x %d", got.xid, got.msgType, m.xid, dhcpRequest)
+	}
+	if id := string(got.options[optClientID]); id != "\x00ep1" {
+		t.Errorf("client id = %q, want a type 0 identifier of the endpoint", id)
+	}
+	for _, code := range []byte{optRequestedIP, optServerID} {
+		if !bytes.Equal(got.options[code], m.options[code]) {
+			t.Errorf("option [ %d ] = % x, want % x", code, got.options[code], m.options[code])
+		}
+	}
+	if len(got.options[optParamRequest]) != 5 {
+		t.Errorf("parameter request list = % x", got.options[optParamRequest])
+	}
+
+	release := &dhcpMessage{msgType: dhcpRelease, xid: 1, ciaddr: net.ParseIP("10.0.0.5"), options: map[byte][]byte{}}
+	b = release.marshal()
+	if !bytes.Equal(b[12:16], net.ParseIP("10.0.0.5").To4()) {
+		t.Errorf("ciaddr = % x, want 10.0.0.5", b[12:16])
+	}
+	if got, _ := parseDhcpMessage(b); got.options[optParamRequest] != nil {
+		t.Errorf("a release carries a parameter request list")
+	}
+}
+
+func TestParseDhcpMessage(t *testing.T) {
+	valid := func(opts ...byte) []byte {
+		b := make([]byte, 240)
+		b[0] = 2
+		copy(b[16:20], net.ParseIP("10.0.0.9").To4())
+		copy(b[236:], dhcpMagic)
+		return append(b, opts...)
+	}
+	tests := []struct {
+		name    string
+		b       []byte
+		msgType byte
+		wantErr bool
+	}{
+		{"offer", valid(optMessageType, 1, dhcpOffer, optEnd), dhcpOffer, false},
+		{"padded", valid(0, 0, optMessageType, 1, dhcpAck, optEnd, 1, 2), dhcpAck, false},
+		{"no end", valid(optMessageType, 1, dhcpNak), dhcpNak, false},
+		{"no type", valid(optEnd), 0, false},
+		{"short", make([]byte, 239), 0, true},
+		{"bad magic", make([]byte, 300), 0, true},
+		{"truncated option", valid(optRouter, 4, 10, 0), 0, true},
+		{"truncated length", valid(optRouter), 0, true},
+	}
+	for _, tt := range tests {
+		m, err := parseDhcpMessage(tt.b)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if m.msgType != tt.msgType || m.op != 2 || !m.yiaddr.Equal(net.ParseIP("10.0.0.9")) {
+			t.Errorf("%s: parsed op %d type %d yiaddr %s", tt.name, m.op, m.msgType, m.yiaddr)
+		}
+	}
+}
+
+func TestOptDuration(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want time.Duration
+	}{
+		{seconds(3600), time.Hour},
+		{seconds(0), 0},
+		{seconds(0xffffffff), 24 * time.Hour},
+		{nil, time.Minute},
+		{[]byte{0, 1}, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := optDuration(tt.b, time.Minute); got != tt.want {
+			t.Errorf("optDuration(% x) = %s, want %s", tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNewLease(t *testing.T) {
+	serverMAC, _ := net.ParseMAC("02:00:00:00:00:01")
+	tests := []struct {
+		name            string
+		options         map[byte][]byte
+		addr, router    string
+		duration, renew time.Duration
+	}{
+		{
+			name: "full ack",
+			options: map[byte][]byte{
+				optSubnetMask:  net.IP(net.CIDRMask(24, 32)),
+				optRouter:      net.ParseIP("10.0.0.1").To4(),
+				optServerID:    net.ParseIP("10.0.0.2").To4(),
+				optLeaseTime:   seconds(600),
+				optRenewalTime: seconds(200),
+			},
+			addr: "10.0.0.9/24", router: "10.0.0.1", duration: 600 * time.Second, renew: 200 * time.Second,
+		},
+		{
+			name:    "defaults",
+			options: map[byte][]byte{optServerID: net.ParseIP("10.0.0.2").To4()},
+			addr:    "10.0.0.9/8", duration: time.Hour, renew: 30 * time.Minute,
+		},
+		{
+			name: "half of the lease",
+			options: map[byte][]byte{
+				optSubnetMask: net.IP(net.CIDRMask(16, 32)),
+				optRouter:     append(net.ParseIP("10.0.0.1").To4(), 10, 0, 0, 254),
+				optLeaseTime:  seconds(120),
+			},
+			addr: "10.0.0.9/16", router: "10.0.0.1", duration: 2 * time.Minute, renew: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		ack := &dhcpMessage{yiaddr: net.ParseIP("10.0.0.9").To4(), options: tt.options}
+		l := newLease("ep1", ack, serverMAC)
+		if l.Addr != tt.addr || l.Router != tt.router || l.Duration != tt.duration || l.Renew != tt.renew {
+			t.Errorf("%s: lease %+v, want addr %s router %q duration %s renew %s", tt.name, l, tt.addr, tt.router, tt.duration, tt.renew)
+		}
+		if l.ClientID != "ep1" || l.ServerMAC != serverMAC.String() {
+			t.Errorf("%s: lease client [ %s ] server mac [ %s ]", tt.name, l.ClientID, l.ServerMAC)
+		}
+	}
+}
+
+func TestLeaseTimers(t *testing.T) {
+	obtained := time.Date(2016, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := &dhcpLease{Obtained: obtained, Duration: time.Hour, Renew: 30 * time.Minute}
+	tests := []struct {
+		after        time.Duration
+		due, expired bool
+	}{
+		{0, false, false},
+		{29 * time.Minute, false, false},
+		{31 * time.Minute, true, false},
+		{61 * time.Minute, true, true},
+	}
+	for _, tt := range tests {
+		now := obtained.Add(tt.after)
+		if l.due(now) != tt.due || l.expired(now) != tt.expired {
+			t.Errorf("after %s: due %t expired %t, want %t %t", tt.after, l.due(now), l.expired(now), tt.due, tt.expired)
+		}
+	}
+}
+
+func TestDhcpMac(t *testing.T) {
+	a, b := dhcpMac("ep1"), dhcpMac("ep2")
+	if a != dhcpMac("ep1") {
+		t.Errorf("dhcpMac is not stable")
+	}
+	if a == b {
+		t.Errorf("dhcpMac(ep1) == dhcpMac(ep2) == %s", a)
+	}
+	if mac, err := net.ParseMAC(a); err != nil || mac[0] != 0x7a || mac[1] != 0x42 {
+		t.Errorf("dhcpMac(ep1) = %s, want a 7a:42 mac", a)
+	}
+}
+
+// udpPacket wraps a payload in the IPv4 and UDP headers dhcpConn.send builds
+func udpPacket(payload []byte, src, dst net.IP, srcPort, dstPort uint16) []byte {
+	pkt := make([]byte, 28+len(payload))
+	pkt[0] = 0x45
+	binary.BigEndian.PutUint16(pkt[2:], uint16(len(pkt)))
+	pkt[8] = 64
+	pkt[9] = syscall.IPPROTO_UDP
+	copy(pkt[12:16], src.To4())
+	copy(pkt[16:20], dst.To4())
+	binary.BigEndian.PutUint16(pkt[10:], checksum(pkt[:20]))
+	binary.BigEndian.PutUint16(pkt[20:], srcPort)
+	binary.BigEndian.PutUint16(pkt[22:], dstPort)
+	binary.BigEndian.PutUint16(pkt[24:], uint16(8+len(payload)))
+	copy(pkt[28:], payload)
+	return pkt
+}
+
+func TestParseReply(t *testing.T) {
+	reply := make([]byte, 240)
+	reply[0] = 2
+	copy(reply[236:], dhcpMagic)
+	reply = append(reply, optMessageType, 1, dhcpAck, optEnd)
+	src, dst := net.ParseIP("10.0.0.1"), net.IPv4bcast
+	if m := parseReply(udpPacket(reply, src, dst, dhcpServerPort, dhcpClientPort)); m == nil || m.msgType != dhcpAck {
+		t.Errorf("parseReply did not decode an ack to the client port")
+	}
+	if m := parseReply(udpPacket(reply, src, dst, dhcpClientPort, dhcpServerPort)); m != nil {
+		t.Errorf("parseReply decoded a message to the server port")
+	}
+	pkt := udpPacket(reply, src, dst, dhcpServerPort, dhcpClientPort)
+	if m := parseReply(pkt[:len(pkt)-10]); m != nil {
+		t.Errorf("parseReply decoded a truncated packet")
+	}
+	if m := parseReply(reply[:20]); m != nil {
+		t.Errorf("parseReply decoded a packet that is not IPv4")
+	}
+}
+
+// testDhcpServer answers DHCP requests on a link of a network namespace
+type testDhcpServer struct {
+	fd       int
+	ifIndex  int
+	serverIP net.IP
+	leaseIP  net.IP
+	clientID string
+	releases chan net.IP
+	stop     chan bool
+	done     chan bool
+}
+
+func (s *testDhcpServer) reply(req *dhcpMessage, chaddr []byte, msgType byte) error {
+	b := make([]byte, 236)
+	b[0], b[1], b[2] = 2, 1, 6
+	binary.BigEndian.PutUint32(b[4:], req.xid)
+	binary.BigEndian.PutUint16(b[10:], 0x8000)
+	copy(b[16:20], s.leaseIP.To4())
+	copy(b[28:44], chaddr)
+	b = append(b, dhcpMagic...)
+	b = append(b, optMessageType, 1, msgType)
+	b = append(b, optServerID, 4)
+	b = append(b, s.serverIP.To4()...)
+	b = append(b, optSubnetMask, 4, 255, 255, 255, 0)
+	b = append(b, optRouter, 4)
+	b = append(b, s.serverIP.To4()...)
+	b = append(b, optLeaseTime, 4)
+	b = append(b, seconds(120)...)
+	b = append(b, optRenewalTime, 4)
+	b = append(b, seconds(60)...)
+	b = append(b, optEnd)
+	pkt := udpPacket(b, s.serverIP, net.IPv4bcast, dhcpServerPort, dhcpClientPort)
+	return sendPacket(s.fd, s.ifIndex, syscall.ETH_P_IP, broadcastMAC, pkt)
+}
+
+func (s *testDhcpServer) serve(t *testing.T) {
+	defer close(s.done)
+	buf := make([]byte, 65536)
+	for {
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+		n, _, err := recvPacket(s.fd, buf)
+		if err != nil {
+			t.Errorf("dhcp server: %s", err)
+			return
+		}
+		pkt := buf[:n]
+		if n < 28 || pkt[9] != syscall.IPPROTO_UDP || binary.BigEndian.Uint16(pkt[22:]) != dhcpServerPort {
+			continue
+		}
+		req, err := parseDhcpMessage(pkt[28:])
+		if err != nil || string(req.options[optClientID]) != "\x00"+s.clientID {
+			continue
+		}
+		chaddr := append([]byte(nil), pkt[28+28:28+34]...)
+		switch req.msgType {
+		case dhcpDiscover:
+			err = s.reply(req, chaddr, dhcpOffer)
+		case dhcpRequest:
+			if !net.IP(req.options[optRequestedIP]).Equal(s.leaseIP) {
+				err = s.reply(req, chaddr, dhcpNak)
+			} else {
+				err = s.reply(req, chaddr, dhcpAck)
+			}
+		case dhcpRelease:
+			s.releases <- net.IP(append([]byte(nil), pkt[28+12:28+16]...))
+		}
+		if err != nil {
+			t.Errorf("dhcp server: %s", err)
+		}
+	}
+}
+
+func run(t *testing.T, args ...string) {
+	if out, err := exec.Command(args[0], args[1:]...).CombinedOutput(); err != nil {
+		t.Skipf("unable to set up the DHCP namespace, %v: %s %s", args, err, out)
+	}
+}
+
+// TestAcquireLease leases, renews and releases an endpoint address against
+// a DHCP server running in a network namespace at the end of a veth pair
+func TestAcquireLease(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("the DHCP lease test needs root to create a network namespace")
+	}
+	ns := fmt.Sprintf("ipvdhcp%d", os.Getpid())
+	client, server := "ipvdc0", "ipvds0"
+	run(t, "ip", "netns", "add", ns)
+	defer exec.Command("ip", "netns", "del", ns).Run()
+	run(t, "ip", "link", "add", client, "type", "veth", "peer", "name", server)
+	defer exec.Command("ip", "link", "del", client).Run()
+	run(t, "ip", "link", "set", server, "netns", ns)
+	run(t, "ip", "-n", ns, "addr", "add", "10.77.0.1/24", "dev", server)
+	run(t, "ip", "-n", ns, "link", "set", server, "up")
+	run(t, "ip", "link", "set", client, "up")
+
+	srv := &testDhcpServer{
+		serverIP: net.ParseIP("10.77.0.1"),
+		leaseIP:  net.ParseIP("10.77.0.50"),
+		clientID: "ep-dhcp-test",
+		releases: make(chan net.IP, 1),
+		stop:     make(chan bool),
+		done:     make(chan bool),
+	}
+	// the socket stays in the namespace it was opened in
+	err := withNetns("/var/run/netns/"+ns, func() error {
+		link, err := netlink.LinkByName(server)
+		if err != nil {
+			return err
+		}
+		srv.ifIndex = link.Attrs().Index
+		srv.fd, err = packetSocket(srv.ifIndex, syscall.ETH_P_IP, 100*time.Millisecond)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer syscall.Close(srv.fd)
+	go srv.serve(t)
+	defer func() {
+		close(srv.stop)
+		<-srv.done
+	}()
+
+	n := &network{id: "net-dhcp", ifaceOpt: client, modeOpt: ipVlanL2, dhcp: true, endpoints: endpointTable{}}
+	ep := &endpoint{id: srv.clientID}
+	lease, err := acquireLease(n, ep, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Addr != "10.77.0.50/24" || lease.Router != "10.77.0.1" || lease.Server != "10.77.0.1" {
+		t.Errorf("lease %+v, want 10.77.0.50/24 from 10.77.0.1", lease)
+	}
+	if lease.Duration != 2*time.Minute || lease.Renew != time.Minute {
+		t.Errorf("lease duration %s renew %s, want 2m0s and 1m0s", lease.Duration, lease.Renew)
+	}
+
+	ep.lease = lease
+	renewed, err := acquireLease(n, ep, lease.ipNet().IP)
+	if err != nil {
+		t.Fatalf("renewal: %s", err)
+	}
+	if renewed.Addr != lease.Addr || !renewed.Obtained.After(lease.Obtained) {
+		t.Errorf("renewed lease %+v of %+v", renewed, lease)
+	}
+	if _, err := acquireLease(n, ep, net.ParseIP("10.77.0.99")); err == nil {
+		t.Errorf("a declined request did not fail")
+	}
+
+	if err := releaseLease(n, ep); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ip := <-srv.releases:
+		if !ip.Equal(srv.leaseIP) {
+			t.Errorf("released [ %s ], want [ %s ]", ip, srv.leaseIP)
+		}
+	case <-time.After(2 * time.Second):
+		t.Errorf("the server did not see the release")
+	}
+}
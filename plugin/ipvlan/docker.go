@@ -3,5 +3,5 @@ package ipvlan
 import "github.com/samalba/dockerclient"
 
 type dockerer struct {
-	client *dockerclient.DockerClient
+	client dockerclient.Client
 }
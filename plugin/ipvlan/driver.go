@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
@@ -21,13 +26,14 @@ import (
 const (
 	MethodReceiver     = "NetworkDriver"
 	defaultRoute       = "0.0.0.0/0"
+	defaultRouteV6     = "::/0"
 	containerEthPrefix = "eth"
 	ipVlanL2           = "l2"
 	ipVlanL3           = "l3"
+	ipVlanL3S          = "l3s"
 	ipVlanL3Routing    = "l3routing"
 	minMTU             = 68
 	defaultMTU         = 1500
-	TxQueueLen         = 0
 )
 
 type ipvlanType netlink.IPVlanMode
@@ -40,12 +46,29 @@ const (
 
 type Driver interface {
 	Listen(string) error
+	ListenMacvlan(string) error
 }
 
 type driver struct {
 	dockerer
 	networks   networkTable
+	ownedLinks map[string]bool
+	// parent links held by the networks being created, by link name
+	linkHolds  map[string]map[string]bool
 	nameserver string
+	driverName string
+	// driver name of the macvlan socket, empty when it is not served
+	macvlanName string
+	store       *stateStore
+	// networks loaded from the state file, the only ones reconcile prunes
+	restored map[string]bool
+	// pools of the IPAM driver served on the same sockets
+	ipam ipam
+	// started with the first dhcp network
+	renewOnce sync.Once
+	// l3routing networks whose prefix waits for the routing manager
+	pendingAdverts map[string]bool
+	advertRetrying bool
 	pluginConfig
 	sync.Mutex
 }
@@ -53,10 +76,15 @@ type driver struct {
 // Struct for binding plugin specific configurations (cli.go for details).
 type pluginConfig struct {
 	mtu             int
+	txQueueLen      int
 	mode            string
 	hostIface       string
+	gcInterval      time.Duration
 	containerSubnet *net.IPNet
 	gatewayIP       net.IP
+	// the routing manager l3routing networks advertise through, empty when
+	// the plugin was not started in l3routing mode
+	routingManager string
 }
 
 type pluginNet struct {
@@ -68,7 +96,19 @@ type endpoint struct {
 	id      string
 	mac     net.HardwareAddr
 	addr    *net.IPNet
+	addrV6  *net.IPNet
 	srcName string
+	// static routes requested when the endpoint was created
+	routes []*route
+	// set while joined, the ipvlan link keeps its ifindex when moved
+	sandboxKey string
+	ifIndex    int
+	// bandwidth limits applied at join
+	limits rateLimit
+	// -p port bindings published on the parent address
+	portMap []types.PortBinding
+	// DHCP lease of the address on dhcp networks
+	lease *dhcpLease
 }
 
 type endpointTable map[string]*endpoint
@@ -83,15 +123,19 @@ func New(version string, ctx *cli.Context) (Driver, error) {
 	if ok := validateHostIface(ctx.String("host-interface")); !ok {
 		log.Debugf("Field [ host-interface ] not detected. Assuming it will be passed via docker network -o (opts)")
 	}
-	// lower bound of v4 MTU is 68-bytes per rfc791
+	// lower bound of v4 MTU is 68-bytes per rfc791, unset inherits the parent MTU
 	if ctx.Int("mtu") <= 0 {
-		cliMTU = defaultMTU
+		cliMTU = 0
 	} else if ctx.Int("mtu") >= minMTU {
 		cliMTU = ctx.Int("mtu")
 	} else {
 		log.Fatalf("The MTU value passed [ %d ] must be greater then [ %d ] bytes per rfc791", ctx.Int("mtu"), minMTU)
 	}
+	if ctx.Int("txqueuelen") < 0 {
+		log.Fatalf("The txqueuelen value passed [ %d ] must be a positive number", ctx.Int("txqueuelen"))
+	}
 
+	var managermode string
 	switch ctx.String("mode") {
 	case ipVlanL2:
 		ipVlanMode = ipVlanL2
@@ -99,12 +143,15 @@ func New(version string, ctx *cli.Context) (Driver, error) {
 		// IPVlan simply needs the container interface for its
 		// default route target since only unicast is allowed <3
 		ipVlanMode = ipVlanL3
+	case ipVlanL3S:
+		// l3 with the container traffic passing the host netfilter hooks
+		ipVlanMode = ipVlanL3S
 	case ipVlanL3Routing:
 		// IPVlan simply needs the container interface for its
 		// default route target since only unicast is allowed <3
 		ipVlanMode = ipVlanL3Routing
 		//containerGW = nil
-		managermode := ""
+		managermode = routingManager
 		as := "65000"
 		if ctx.String("routemng") != "" {
 			managermode = ctx.String("routemng")
@@ -112,48 +159,123 @@ func New(version string, ctx *cli.Context) (Driver, error) {
 		if ctx.String("as") != "" {
 			as = ctx.String("as")
 		}
+		routingIface, err := resolveAutoIface(ipVlanEthIface, "")
+		if err != nil {
+			return nil, err
+		}
 		// Initialize Routing monitoring
-		go routing.InitRoutingMonitering(ipVlanEthIface, managermode, as)
+		go routing.InitRoutingMonitering(routingIface, managermode, as)
 
 	default:
 		log.Debugf("Field [ mode ] not detected. Assuming it will be passed via docker network -o (opts)")
 	}
 
+	store, err := newStateStore(ctx.String("state-dir"))
+	if err != nil {
+		return nil, err
+	}
+	state, err := store.load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the persisted driver state: %s", err)
+	}
+	networks := state.networkTable()
+	restored := map[string]bool{}
+	for id, n := range networks {
+		restored[id] = true
+		// records written before the mode was pinned get the current one
+		n.pinMode(ipVlanMode)
+	}
+	log.Debugf("Restored [ %d ] networks from the state directory [ %s ]", len(networks), ctx.String("state-dir"))
+
 	pluginOpts := &pluginConfig{
-		mtu:       cliMTU,
-		mode:      ipVlanMode,
-		hostIface: ipVlanEthIface,
+		mtu:        cliMTU,
+		txQueueLen: ctx.Int("txqueuelen"),
+		mode:       ipVlanMode,
+		hostIface:  ipVlanEthIface,
+		gcInterval: ctx.Duration("gc-interval"),
+		// networks in l3routing mode need the manager started above
+		routingManager: managermode,
+	}
+
+	// libnetwork names a remote driver after its socket file
+	driverName := strings.TrimSuffix(filepath.Base(ctx.String("socket")), ".sock")
+	var macvlanName string
+	if socket := ctx.String("macvlan-socket"); socket != "" {
+		macvlanName = strings.TrimSuffix(filepath.Base(socket), ".sock")
 	}
 
 	d := &driver{
-		networks: networkTable{},
+		networks:   networks,
+		ownedLinks: state.ownedLinks(),
 		dockerer: dockerer{
 			client: docker,
 		},
+		driverName:   driverName,
+		macvlanName:  macvlanName,
+		store:        store,
+		restored:     restored,
+		ipam:         ipam{pools: state.poolTable()},
 		pluginConfig: *pluginOpts,
 	}
+	go d.startReconcile()
+	go d.watchParents()
+	for _, n := range networks {
+		if n.dhcp {
+			d.startLeaseRenewal()
+		}
+	}
+	if interval := ctx.Duration("gc-interval"); interval > 0 {
+		go newCollector(d, interval, !ctx.Bool("gc-delete")).run()
+	}
 	return d, nil
 }
 
+// Listen serves the ipvlan driver on socket
 func (driver *driver) Listen(socket string) error {
+	return driver.listen(socket, driverKindIpvlan)
+}
+
+// ListenMacvlan serves the macvlan driver on socket, both drivers share the
+// network and endpoint tables
+func (driver *driver) ListenMacvlan(socket string) error {
+	return driver.listen(socket, driverKindMacvlan)
+}
+
+func (driver *driver) listen(socket, kind string) error {
 	router := mux.NewRouter()
 	router.NotFoundHandler = http.HandlerFunc(notFound)
 
 	router.Methods("POST").Path("/Plugin.Activate").HandlerFunc(driver.handshake)
-	router.Methods("POST").Path("/NetworkDriver.GetCapabilities").HandlerFunc(driver.capabilities)
+	router.Methods("POST").Path("/NetworkDriver.GetCapabilities").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		driver.capabilities(w, r, kind)
+	})
 
 	handleMethod := func(method string, h http.HandlerFunc) {
 		router.Methods("POST").Path(fmt.Sprintf("/%s.%s", MethodReceiver, method)).HandlerFunc(h)
 	}
-	handleMethod("CreateNetwork", driver.createNetwork)
+	handleMethod("CreateNetwork", func(w http.ResponseWriter, r *http.Request) {
+		driver.createNetwork(w, r, kind)
+	})
 	handleMethod("DeleteNetwork", driver.deleteNetwork)
 	handleMethod("CreateEndpoint", driver.createEndpoint)
 	handleMethod("DeleteEndpoint", driver.deleteEndpoint)
 	handleMethod("EndpointOperInfo", driver.infoEndpoint)
 	handleMethod("Join", driver.joinEndpoint)
 	handleMethod("Leave", driver.leaveEndpoint)
+	handleMethod("ProgramExternalConnectivity", driver.programExternalConnectivity)
+	handleMethod("RevokeExternalConnectivity", driver.revokeExternalConnectivity)
 	handleMethod("DiscoverNew", driver.discoverNew)
 	handleMethod("DiscoverDelete", driver.discoverDelete)
+
+	handleIpam := func(method string, h http.HandlerFunc) {
+		router.Methods("POST").Path(fmt.Sprintf("/%s.%s", ipamReceiver, method)).HandlerFunc(h)
+	}
+	handleIpam("GetCapabilities", driver.ipamCapabilities)
+	handleIpam("GetDefaultAddressSpaces", driver.getDefaultAddressSpaces)
+	handleIpam("RequestPool", driver.requestPool)
+	handleIpam("ReleasePool", driver.releasePool)
+	handleIpam("RequestAddress", driver.requestAddress)
+	handleIpam("ReleaseAddress", driver.releaseAddress)
 	var (
 		listener net.Listener
 		err      error
@@ -177,9 +299,12 @@ func sendError(w http.ResponseWriter, msg string, code int) {
 	http.Error(w, msg, code)
 }
 
+// errorResponsef fails a libnetwork request, libnetwork surfaces Err to the user
 func errorResponsef(w http.ResponseWriter, fmtString string, item ...interface{}) {
+	msg := fmt.Sprintf(fmtString, item...)
+	log.Errorf("Request failed: %s", msg)
 	json.NewEncoder(w).Encode(map[string]string{
-		"Err": fmt.Sprintf(fmtString, item...),
+		"Err": msg,
 	})
 }
 
@@ -200,10 +325,10 @@ type handshakeResp struct {
 
 func (driver *driver) handshake(w http.ResponseWriter, r *http.Request) {
 	err := json.NewEncoder(w).Encode(&handshakeResp{
-		[]string{"NetworkDriver"},
+		[]string{"NetworkDriver", ipamReceiver},
 	})
 	if err != nil {
-		log.Fatalf("handshake encode: %s", err)
+		log.Errorf("handshake encode: %s", err)
 		sendError(w, "encode error", http.StatusInternalServerError)
 		return
 	}
@@ -214,16 +339,16 @@ type capabilitiesResp struct {
 	Scope string
 }
 
-func (driver *driver) capabilities(w http.ResponseWriter, r *http.Request) {
+func (driver *driver) capabilities(w http.ResponseWriter, r *http.Request, kind string) {
 	var driver_scope = "local"
-	if ipVlanMode == ipVlanL3Routing {
+	if kind == driverKindIpvlan && driver.pluginConfig.mode == ipVlanL3Routing {
 		driver_scope = "global"
 	}
 	err := json.NewEncoder(w).Encode(&capabilitiesResp{
 		driver_scope,
 	})
 	if err != nil {
-		log.Fatalf("capabilities encode: %s", err)
+		log.Errorf("capabilities encode: %s", err)
 		sendError(w, "encode error", http.StatusInternalServerError)
 		return
 	}
@@ -234,97 +359,234 @@ type networkCreate struct {
 	NetworkID string
 	Options   map[string]interface{}
 	IpV4Data  []driverapi.IPAMData
-	ipV6Data  []driverapi.IPAMData
+	IpV6Data  []driverapi.IPAMData
 }
 
-func (driver *driver) createNetwork(w http.ResponseWriter, r *http.Request) {
+func (driver *driver) createNetwork(w http.ResponseWriter, r *http.Request, kind string) {
 	var create networkCreate
 	err := json.NewDecoder(r.Body).Decode(&create)
 	if err != nil {
-		sendError(w, "Unable to decode JSON payload: "+err.Error(), http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	var netCidr *net.IPNet
 	var netGw string
+	auxAddrs := map[string]net.IP{}
 	log.Debugf("Network Create Called: [ %+v ]", create)
 	for _, v4 := range create.IpV4Data {
-		netGw = v4.Gateway.IP.String()
+		if v4.Gateway != nil {
+			netGw = v4.Gateway.IP.String()
+		}
 		netCidr = v4.Pool
+		for name, aux := range v4.AuxAddresses {
+			auxAddrs[name] = aux.IP
+		}
 	}
 	n := &network{
 		id:        create.NetworkID,
+		kind:      kind,
 		endpoints: endpointTable{},
 		cidr:      netCidr,
 		gateway:   netGw,
 	}
-	// Parse docker network -o opts
-	for k, v := range create.Options {
-		if k == "com.docker.network.generic" {
-			if genericOpts, ok := v.(map[string]interface{}); ok {
-				for key, val := range genericOpts {
-					log.Debugf("Libnetwork Opts Sent: [ %s ] Value: [ %s ]", key, val)
-					// Parse -o mode from libnetwork generic opts
-					if key == "mode" {
-						switch val {
-						case ipVlanL2:
-							log.Debugf("Ipvlan mode is L2 [ %s ]", val)
-							// backward compatable
-							ipVlanMode = ipVlanL2
-							// new API
-							n.modeOpt = ipVlanL2
-						case ipVlanL3:
-							log.Debugf("Ipvlan mode is L3 [ %s ]", val)
-							// IPVlan simply needs the container interface for its
-							// default route target since only unicast is allowed <3
-							// backward compatable
-							ipVlanMode = ipVlanL3
-							// new API
-							n.modeOpt = ipVlanL3
-						case ipVlanL3Routing:
-							// IPVlan simply needs the container interface for its
-							// default route target since only unicast is allowed <3
-							ipVlanMode = ipVlanL3Routing
-							n.modeOpt = ipVlanL3Routing
-						}
-					}
-					// Parse -o host_iface from libnetwork generic opts
-					if key == "host_iface" {
-						n.ifaceOpt = val.(string)
-					}
-				}
-			}
+	for _, v6 := range create.IpV6Data {
+		if v6.Gateway != nil {
+			n.gatewayV6 = v6.Gateway.IP.String()
 		}
+		n.cidrV6 = v6.Pool
+	}
+	opts, err := driver.parseNetworkOptions(kind, create.Options)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	n.modeOpt = opts["mode"]
+	n.ifaceOpt = opts["host_iface"]
+	parentOpt := opts["parent"]
+	// host_iface=auto picks the parent from the host routing table
+	if n.ifaceOpt == "" && parentOpt == "" {
+		n.ifaceOpt = ipVlanEthIface
+	}
+	gateway := n.gateway
+	if gateway == "" {
+		gateway = n.gatewayV6
+	}
+	if n.ifaceOpt, err = resolveAutoIface(n.ifaceOpt, gateway); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if parentOpt, err = resolveAutoIface(parentOpt, gateway); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if n.ifaceOpt, err = resolveParentIface(n.ifaceOpt, parentOpt, opts["vlan_id"]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
 	}
 	if n.ifaceOpt == "" {
 		n.ifaceOpt = ipVlanEthIface
 	}
+	n.pinMode(driver.pluginConfig.mode)
+	if err := parseIpvlanFlag(n, opts["ipvlan_flag"]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if other := driver.parentModeConflict(n); other != nil {
+		errorResponsef(w, "parent interface [ %s ] is already used by network [ %s ] in %s mode [ %s ], "+
+			"all networks on one parent must share the driver, the kernel ipvlan mode and the ipvlan_flag", n.ifaceOpt, other.id, other.driverKind(), other.mode())
+		return
+	}
+	if n.limits, err = parseRateLimit(rateLimit{}, opts.get); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseDhcpOpt(n, opts[dhcpOpt]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseDadOpt(n, opts[dadOpt]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseAnnounceOpts(n, opts[announceCountOpt], opts[announceIntervalOpt]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseBondOpts(n, opts["bond_slaves"], opts["bond_mode"]); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	// create the bond and 802.1Q sub-interface when the parent is one that is missing
+	if err := driver.ensureParentLinks(n); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	mtuOpt := opts["mtu"]
+	if mtuOpt == "" {
+		mtuOpt = opts[driverMTUOpt]
+	}
+	if err := driver.resolveLinkProfile(n, mtuOpt, opts["txqueuelen"]); err != nil {
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseNatOpts(n, opts["nat"], opts["snat_ip"]); err != nil {
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := parseHostShim(n, opts["host_shim"], auxAddrs, &driver.ipam); err != nil {
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if n.routes, err = parseRoutes(n, opts[routesOpt]); err != nil {
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := natOut(n); err != nil {
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "unable to install the outbound NAT rule for [ %s ]: %s", n.cidr, err)
+		return
+	}
+	if err := installNetworkRoutes(n); err != nil {
+		natOutDel(n)
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "unable to install the routes for network [ %s ]: %s", n.id, err)
+		return
+	}
+	if err := ensureHostShim(n); err != nil {
+		removeHostShim(n)
+		driver.teardownNetworkRoutes(n)
+		natOutDel(n)
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "%s", err)
+		return
+	}
 	driver.addNetwork(n)
+	driver.checkParent(n)
+	if err := driver.saveState(); err != nil {
+		driver.delNetwork(n.id)
+		removeHostShim(n)
+		driver.teardownNetworkRoutes(n)
+		natOutDel(n)
+		driver.releaseParentLinks(n)
+		errorResponsef(w, "unable to persist network [ %s ]: %s", n.id, err)
+		return
+	}
 	emptyResponse(w)
+}
 
-	if ipVlanMode == ipVlanL3 {
-		log.Debugf("Adding route for the local ipvlan subnet [ %s ] in the default namespace using the specified host interface [ %s]", netCidr.String(), n.ifaceOpt)
-		ipvlanParent, err := netlink.LinkByName(n.ifaceOpt)
-		// Add a route in the default NS to point to the IPVlan namespace subnet
-		addRouteIface(netCidr, ipvlanParent)
-		if err != nil {
-			log.Debugf("a problem occurred adding the container subnet default namespace route", err)
+// installNetworkRoutes adds the default namespace link routes of an L3
+// network pointing at the ipvlan subnets and advertises the IPv4 prefix of
+// l3routing networks. Routes that already exist are kept.
+func installNetworkRoutes(n *network) error {
+	added, err := addNetworkRoutes(n)
+	if err != nil {
+		return err
+	}
+	if err := advertiseNetwork(n); err != nil {
+		// undo the routes added by this call, routes that existed are kept
+		delNetworkRoutes(n, added)
+		return err
+	}
+	return nil
+}
+
+// addNetworkRoutes adds the default namespace link routes of an L3 network
+// and returns the ones that did not exist yet
+func addNetworkRoutes(n *network) ([]*net.IPNet, error) {
+	if !n.l3() {
+		return nil, nil
+	}
+	ipvlanParent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		return nil, fmt.Errorf("parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+	}
+	var added []*net.IPNet
+	for _, subnet := range n.subnets() {
+		log.Debugf("Adding route for the local ipvlan subnet [ %s ] in the default namespace using the specified host interface [ %s ]", subnet, n.ifaceOpt)
+		err := addRouteIface(subnet, ipvlanParent)
+		if err == syscall.EEXIST {
+			continue
 		}
-	} else if ipVlanMode == ipVlanL3Routing {
-		log.Debugf("Adding route for the local ipvlan subnet [ %s ] in the default namespace using the specified host interface [ %s]", netCidr.String(), n.ifaceOpt)
-		ipvlanParent, err := netlink.LinkByName(n.ifaceOpt)
-		// Add a route in the default NS to point to the IPVlan namespace subnet
-		addRouteIface(netCidr, ipvlanParent)
 		if err != nil {
-			log.Debugf("a problem occurred adding the container subnet default namespace route", err)
+			delNetworkRoutes(n, added)
+			return nil, fmt.Errorf("unable to add the route for [ %s ]: %s", subnet, err)
 		}
+		added = append(added, subnet)
+	}
+	return added, nil
+}
 
+// delNetworkRoutes removes default namespace link routes of a network
+func delNetworkRoutes(n *network, subnets []*net.IPNet) {
+	ipvlanParent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		return
+	}
+	for _, subnet := range subnets {
+		delRouteIface(subnet, ipvlanParent)
+	}
+}
+
+// advertiseNetwork announces the IPv4 prefix of an l3routing network
+func advertiseNetwork(n *network) error {
+	if n.mode() != ipVlanL3Routing {
+		return nil
+	}
+	if n.cidr != nil {
 		// Announce the local IPVLAN network to the other peers in the BGP cluster
-		log.Infof("New Docker network: [ %s ]", netCidr.String())
-		err = routing.AdvertizeNewRoute(netCidr)
-		if err != nil {
-			log.Fatalf("Error installing container route : %s", err)
+		log.Infof("New Docker network: [ %s ]", n.cidr)
+		if err := routing.AdvertizeNewRoute(n.cidr); err != nil {
+			return fmt.Errorf("unable to advertise [ %s ]: %s", n.cidr, err)
 		}
 	}
+	if n.cidrV6 != nil {
+		log.Warnf("The routing manager only advertises IPv4, the IPv6 subnet [ %s ] must be routed to this host by other means", n.cidrV6)
+	}
+	return nil
 }
 
 // addRouteIface required for L3 mode adds a link scoped route in the default ns
@@ -342,13 +604,88 @@ type networkDelete struct {
 
 func (driver *driver) deleteNetwork(w http.ResponseWriter, r *http.Request) {
 	var delete networkDelete
-	nid := delete.NetworkID
 	if err := json.NewDecoder(r.Body).Decode(&delete); err != nil {
-		sendError(w, "Unable to decode JSON payload: "+err.Error(), http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Delete network request: %+v", &delete)
+	nid := delete.NetworkID
+	n, err := driver.getNetwork(nid)
+	if err != nil {
+		// nothing left to tear down, let libnetwork finish removing it
+		log.Warnf("Delete requested for a network unknown to the driver [ %s ]: %s", nid, err)
+		emptyResponse(w)
+		return
+	}
+	if count := n.liveEndpointCount(); count > 0 {
+		errorResponsef(w, "network [ %s ] still has [ %d ] active endpoints", nid, count)
+		return
+	}
+	if err := driver.teardownNetwork(n); err != nil {
+		errorResponsef(w, "unable to tear down network [ %s ]: %s", nid, err)
+		return
+	}
 	driver.delNetwork(nid)
+	if err := driver.saveState(); err != nil {
+		errorResponsef(w, "unable to persist the removal of network [ %s ]: %s", nid, err)
+		return
+	}
+	emptyResponse(w)
+	log.Debugf("Deleted network [ %s ]", nid)
+}
+
+// teardownNetwork removes the host resources created for a network. Missing
+// routes are not an error so a partially created network can be deleted.
+func (driver *driver) teardownNetwork(n *network) error {
+	n.hostLock.Lock()
+	defer n.hostLock.Unlock()
+	// the parent watcher must not restore what is being removed
+	n.deleting = true
+	// endpoints that were never joined or whose sandbox is gone do not
+	// block the delete, their leases and shim routes go with the network
+	for _, ep := range n.endpointCopies() {
+		log.Infof("Releasing the remaining endpoint [ %s ] of network [ %s ]", ep.id, n.id)
+		releaseEndpoint(n, ep)
+		n.deleteEndpoint(ep.id)
+	}
+	if err := removeHostShim(n); err != nil {
+		return fmt.Errorf("unable to delete the host shim of network [ %s ]: %s", n.id, err)
+	}
+	if err := driver.teardownNetworkRoutes(n); err != nil {
+		return err
+	}
+	if err := natOutDel(n); err != nil {
+		return fmt.Errorf("unable to remove the outbound NAT rule for [ %s ]: %s", n.cidr, err)
+	}
+	driver.releaseParentLinks(n)
+	return nil
+}
+
+// teardownNetworkRoutes withdraws the BGP prefix and removes the default
+// namespace link route of an L3 network
+func (driver *driver) teardownNetworkRoutes(n *network) error {
+	if !n.l3() {
+		return nil
+	}
+	mode := n.mode()
+	if mode == ipVlanL3Routing && n.cidr != nil {
+		log.Infof("Withdrawing the deleted Docker network [ %s ]", n.cidr)
+		if err := routing.WithdrawRoute(n.cidr); err != nil {
+			return fmt.Errorf("unable to withdraw the BGP prefix [ %s ]: %s", n.cidr, err)
+		}
+	}
+	ipvlanParent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		log.Warnf("Parent interface [ %s ] is gone, skipping the removal of the routes for [ %v ]", n.ifaceOpt, n.subnets())
+		return nil
+	}
+	for _, subnet := range n.subnets() {
+		log.Debugf("Removing the route for the ipvlan subnet [ %s ] from the default namespace", subnet)
+		if err := delRouteIface(subnet, ipvlanParent); err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("unable to remove the route for [ %s ]: %s", subnet, err)
+		}
+	}
+	return nil
 }
 
 // delRouteIface clean up the required L3 mode default ns route
@@ -387,25 +724,109 @@ type endpointResponse struct {
 func (driver *driver) createEndpoint(w http.ResponseWriter, r *http.Request) {
 	var create endpointCreate
 	if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
-		sendError(w, "Unable to decode JSON payload: "+err.Error(), http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	endID := create.EndpointID
+	n, err := driver.getNetwork(create.NetworkID)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if create.Interface == nil {
+		if !n.dhcp {
+			errorResponsef(w, "endpoint [ %s ] has no interface, the driver requires addresses from libnetwork ipam", endID)
+			return
+		}
+		create.Interface = &EndpointInterface{}
+	}
 	log.Debugf("The container subnet for this context is [ %s ]", create.Interface.Address)
 	// Request an IP address from libnetwork based on the cidr scope
 	// TODO: Add a user defined static ip addr option in Docker v1.10
 	containerAddress := create.Interface.Address
-	if containerAddress == "" {
-		log.Errorf("Unable to obtain an IP address from libnetwork default ipam")
+	containerAddressV6 := create.Interface.AddressIPv6
+	if n.dhcp && containerAddress != "" {
+		errorResponsef(w, "network [ %s ] leases its addresses over DHCP, create it with --ipam-driver=null", n.id)
+		return
+	}
+	if containerAddress == "" && containerAddressV6 == "" && !n.dhcp {
+		errorResponsef(w, "unable to obtain an IP address for endpoint [ %s ] from libnetwork ipam", endID)
+		return
+	}
+	opts, err := parseOptions(scopeEndpoint, n.driverKind(), create.Options)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	ep := &endpoint{id: endID}
+	if ep.routes, err = parseRoutes(n, opts[routesOpt]); err != nil {
+		errorResponsef(w, "%s", err)
 		return
 	}
-	// generate a mac address for the pending container
-	mac := makeMac(net.ParseIP(containerAddress))
-	log.Infof("Allocated container IP: [ %s ]", containerAddress)
+	if containerAddress != "" {
+		if ep.addr, err = netlink.ParseIPNet(containerAddress); err != nil {
+			errorResponsef(w, "invalid endpoint address [ %s ]: %s", containerAddress, err)
+			return
+		}
+	}
+	if containerAddressV6 != "" {
+		if ep.addrV6, err = netlink.ParseIPNet(containerAddressV6); err != nil {
+			errorResponsef(w, "invalid endpoint IPv6 address [ %s ]: %s", containerAddressV6, err)
+			return
+		}
+	}
+	// generate a mac address for the pending container, v6 only endpoints
+	// derive it from the low order bytes of their IPv6 address
+	var mac string
+	if n.dhcp {
+		mac = dhcpMac(endID)
+	} else if ep.addr != nil {
+		mac = makeMac(ep.addr.IP)
+	} else {
+		mac = makeMac(ep.addrV6.IP)
+	}
+	ep.mac, _ = net.ParseMAC(mac)
+	// dhcp networks lease the IPv4 address from the parent LAN
+	if n.dhcp {
+		if ep.lease, err = acquireLease(n, ep, nil); err != nil {
+			errorResponsef(w, "unable to lease an address for endpoint [ %s ]: %s", endID, err)
+			return
+		}
+		ep.addr = ep.lease.ipNet()
+		containerAddress = ep.lease.Addr
+		log.Infof("Leased [ %s ] from the DHCP server [ %s ] for [ %s ]", ep.lease.Addr, ep.lease.Server, ep.lease.Duration)
+	}
+	if err := detectDuplicates(n, ep); err != nil {
+		releaseLease(n, ep)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if ep.addr != nil {
+		if err := addShimRoute(n, ep.addr.IP); err != nil {
+			releaseLease(n, ep)
+			errorResponsef(w, "%s", err)
+			return
+		}
+	}
+	n.addEndpoint(ep)
+	if err := driver.saveState(); err != nil {
+		n.deleteEndpoint(endID)
+		if ep.addr != nil {
+			delShimRoute(n, ep.addr.IP)
+		}
+		releaseLease(n, ep)
+		errorResponsef(w, "unable to persist endpoint [ %s ]: %s", endID, err)
+		return
+	}
+	log.Infof("Allocated container IP: [ %s ] IPv6: [ %s ]", containerAddress, containerAddressV6)
 	// IP addrs comes from libnetwork ipam via user 'docker network' parameters
 	respIface := &EndpointInterface{
 		MacAddress: mac,
 	}
+	// a leased address is handed back to libnetwork, which left it unset
+	if n.dhcp {
+		respIface.Address = containerAddress
+	}
 	resp := &endpointResponse{
 		Interface: *respIface,
 	}
@@ -414,6 +835,25 @@ func (driver *driver) createEndpoint(w http.ResponseWriter, r *http.Request) {
 	log.Debugf("Create endpoint %s %+v", endID, resp)
 }
 
+// l3DefaultRoutes returns the connected default routes of the address
+// families of an L3 network, only the interface is needed inside the container
+func (n *network) l3DefaultRoutes() []*staticRoute {
+	var routes []*staticRoute
+	if n.cidr != nil {
+		routes = append(routes, &staticRoute{
+			Destination: defaultRoute,
+			RouteType:   types.CONNECTED,
+		})
+	}
+	if n.cidrV6 != nil {
+		routes = append(routes, &staticRoute{
+			Destination: defaultRouteV6,
+			RouteType:   types.CONNECTED,
+		})
+	}
+	return routes
+}
+
 type endpointDelete struct {
 	NetworkID  string
 	EndpointID string
@@ -422,30 +862,45 @@ type endpointDelete struct {
 func (driver *driver) deleteEndpoint(w http.ResponseWriter, r *http.Request) {
 	var delete endpointDelete
 	if err := json.NewDecoder(r.Body).Decode(&delete); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Delete endpoint request: %+v", &delete)
-	emptyResponse(w)
 
+	if n, err := driver.getNetwork(delete.NetworkID); err == nil {
+		// only the link Join recorded is removed, it is normally moved back
+		// to the default namespace by libnetwork. A link that cannot be
+		// deleted is left to the collector so the endpoint record never
+		// outlives the request.
+		if ep := n.endpointCopy(delete.EndpointID); ep != nil {
+			releaseEndpoint(n, ep)
+		}
+		n.deleteEndpoint(delete.EndpointID)
+		if err := driver.saveState(); err != nil {
+			errorResponsef(w, "unable to persist the removal of endpoint [ %s ]: %s", delete.EndpointID, err)
+			return
+		}
+	}
+	emptyResponse(w)
 	log.Debugf("Delete endpoint %s", delete.EndpointID)
+}
 
-	containerLink := delete.EndpointID[:5]
-	// Check the interface to delete exists to avoid a panic if nil
-	if ok := validateHostIface(containerLink); !ok {
-		log.Errorf("The requested interface to delete [ %s ] was not found on the host.", containerLink)
-		return
+// releaseEndpoint removes the host resources of an endpoint, its link,
+// host shim route, port bindings and DHCP lease. Failures are only logged.
+func releaseEndpoint(n *network, ep *endpoint) {
+	if ep.srcName != "" {
+		if err := deleteLink(ep.srcName); err != nil {
+			log.Warnf("Unable to delete the ipvlan link [ %s ] of endpoint [ %s ], leaving it to the collector: %s", ep.srcName, ep.id, err)
+		}
 	}
-	// Get the link handle
-	link, err := netlink.LinkByName(containerLink)
-	if err != nil {
-		log.Errorf("Error looking up link [ %s ] object: [ %v ] error: [ %s ]", link.Attrs().Name, link, err)
-		return
+	if ep.addr != nil {
+		delShimRoute(n, ep.addr.IP)
+	}
+	if err := revokePortMap(ep); err != nil {
+		log.Warnf("Unable to remove the port bindings of endpoint [ %s ]: %s", ep.id, err)
 	}
-	log.Infof("Deleting the unused ipvlan link [ %s ] from the removed container", link.Attrs().Name)
-	// Delete the link
-	if err := netlink.LinkDel(link); err != nil {
-		log.Errorf("Unable to delete the ipvlan link named [ %s ] for the exiting container: %s", link.Attrs().Name, err)
+	if err := releaseLease(n, ep); err != nil {
+		log.Warnf("Unable to release the DHCP lease of endpoint [ %s ]: %s", ep.id, err)
 	}
 }
 
@@ -461,11 +916,21 @@ type endpointInfo struct {
 func (driver *driver) infoEndpoint(w http.ResponseWriter, r *http.Request) {
 	var info endpointInfoReq
 	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Endpoint info request: %+v", &info)
-	objectResponse(w, &endpointInfo{Value: map[string]interface{}{}})
+	n, err := driver.getNetwork(info.NetworkID)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	ep := n.endpointCopy(info.EndpointID)
+	if ep == nil {
+		errorResponsef(w, "endpoint [ %s ] not found in network [ %s ]", info.EndpointID, info.NetworkID)
+		return
+	}
+	objectResponse(w, &endpointInfo{Value: endpointOperInfo(n, ep)})
 	log.Debugf("Endpoint info %s", info.EndpointID)
 }
 
@@ -490,6 +955,7 @@ type staticRoute struct {
 
 type joinResponse struct {
 	Gateway               string
+	GatewayIPv6           string
 	InterfaceName         InterfaceName
 	StaticRoutes          []*staticRoute
 	DisableGatewayService bool
@@ -498,154 +964,128 @@ type joinResponse struct {
 func (driver *driver) joinEndpoint(w http.ResponseWriter, r *http.Request) {
 	var j join
 	if err := json.NewDecoder(r.Body).Decode(&j); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Join request: %+v", &j)
 
 	getID, err := driver.getNetwork(j.NetworkID)
 	if err != nil {
-		log.Errorf("error getting network ID mode [ %s ]: %v", j.NetworkID, err)
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if reason := getID.degradedReason(); reason != "" {
+		errorResponsef(w, "network [ %s ] is degraded, its parent interface [ %s ] %s", j.NetworkID, getID.ifaceOpt, reason)
+		return
 	}
 
 	endID := j.EndpointID
-	// unique name while still on the common netns
-	preMoveName := endID[:5]
-	res := &joinResponse{}
-
-	/* Backward compatable for plugin options */
-	if getID.modeOpt == "" {
-		mode, err := setIpVlanMode(ipVlanMode)
-		if err != nil {
-			log.Errorf("error getting vlan mode [ %v ]: %s", mode, err)
-			return
-		}
-		// Get the link for the master index (Example: the docker host eth iface)
-		hostEth, err := netlink.LinkByName(getID.ifaceOpt)
-		if err != nil {
-			log.Warnf("Error looking up the parent iface [ %s ] error: [ %s ]", getID.ifaceOpt, err)
-		}
-		ipvlan := &netlink.IPVlan{
-			LinkAttrs: netlink.LinkAttrs{
-				Name:        preMoveName,
-				ParentIndex: hostEth.Attrs().Index,
-				TxQLen:      TxQueueLen,
-			},
-			Mode: mode,
-		}
-		if err := netlink.LinkAdd(ipvlan); err != nil {
-			log.Warnf("Failed to create the netlink link: [ %v ] with the "+
-				"error: %s Note: a parent index cannot be link to both ipvlan "+
-				"and ipvlan simultaneously. A new parent index is required", ipvlan, err)
-			log.Warnf("Also check `/var/run/docker/netns/` for orphaned links to unmount and delete, then restart the plugin")
-			log.Warnf("Run this to clean orphaned links 'umount /var/run/docker/netns/* && rm /var/run/docker/netns/*'")
-		}
-		log.Infof("Created ipvlan link: [ %s ] with a mode: [ %s ]", ipvlan.Name, ipVlanMode)
-		// Set the netlink iface MTU, default is 1500
-		if err := netlink.LinkSetMTU(ipvlan, defaultMTU); err != nil {
-			log.Errorf("Error setting the MTU [ %d ] for link [ %s ]: %s", defaultMTU, ipvlan.Name, err)
-		}
-		// Bring the netlink iface up
-		if err := netlink.LinkSetUp(ipvlan); err != nil {
-			log.Warnf("failed to enable the ipvlan netlink link: [ %v ]", ipvlan, err)
-		}
-
-		// SrcName gets renamed to DstPrefix on the container iface
-		ifname := &InterfaceName{
-			SrcName:   ipvlan.Name,
-			DstPrefix: containerEthPrefix,
-		}
-
-		// L2 ipvlan needs an explicit IP for a default GW in the container netns
-		if ipVlanMode == ipVlanL2 {
-			res = &joinResponse{
-				InterfaceName:         *ifname,
-				Gateway:               getID.gateway,
-				DisableGatewayService: false,
-			}
-			defer objectResponse(w, res)
-		}
-		// ipvlan L3 mode doesnt need an IP for a default GW, just an iface dex.
-		if ipVlanMode == ipVlanL3 || ipVlanMode == ipVlanL3Routing {
-			res = &joinResponse{
-				InterfaceName:         *ifname,
-				DisableGatewayService: true,
-			}
-			// Add a default route of only the interface inside the container
-			defaultRoute := &staticRoute{
-				Destination: defaultRoute,
-				RouteType:   types.CONNECTED,
-				NextHop:     "",
-			}
-			res.StaticRoutes = []*staticRoute{defaultRoute}
-		}
-
-	} else {
-		/* Using Docker -o options parameter */
-		mode, err := setIpVlanMode(getID.modeOpt)
-		if err != nil {
-			log.Errorf("error getting vlan mode [ %v ]: %s", mode, err)
-			return
-		}
-		// Get the link for the master index (Example: the docker host eth iface)
-		hostEth, err := netlink.LinkByName(getID.ifaceOpt)
-		log.Debugf("interface... %v", getID)
-		if err != nil {
-			log.Warnf("Error looking up the parent iface [ %s ] error: [ %s ]", getID.ifaceOpt, err)
-		}
-		ipvlan := &netlink.IPVlan{
-			LinkAttrs: netlink.LinkAttrs{
-				Name:        preMoveName,
-				ParentIndex: hostEth.Attrs().Index,
-				TxQLen:      TxQueueLen,
-			},
-			Mode: mode,
-		}
-		if err := netlink.LinkAdd(ipvlan); err != nil {
-			log.Warnf("Failed to create the netlink link: [ %v ] with the "+
-				"error: %s Note: a parent index cannot be link to both ipvlan "+
-				"and ipvlan simultaneously. A new parent index is required", ipvlan, err)
-			log.Warnf("Also check `/var/run/docker/netns/` for orphaned links to unmount and delete, then restart the plugin")
-			log.Warnf("Run this to clean orphaned links 'umount /var/run/docker/netns/* && rm /var/run/docker/netns/*'")
-		}
-		log.Infof("Created ipvlan link: [ %s ] with a mode: [ %s ]", ipvlan.Name, ipVlanMode)
-		// Set the netlink iface MTU, default is 1500
-		if err := netlink.LinkSetMTU(ipvlan, defaultMTU); err != nil {
-			log.Errorf("Error setting the MTU [ %d ] for link [ %s ]: %s", defaultMTU, ipvlan.Name, err)
-		}
-		// Bring the netlink iface up
-		if err := netlink.LinkSetUp(ipvlan); err != nil {
-			log.Warnf("failed to enable the ipvlan netlink link: [ %v ]", ipvlan, err)
-		}
-		// SrcName gets renamed to DstPrefix on the container iface
-		ifname := &InterfaceName{
-			SrcName:   ipvlan.Name,
-			DstPrefix: containerEthPrefix,
+	opts, err := parseOptions(scopeEndpoint, getID.driverKind(), j.Options)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	joinRoutes, err := parseRoutes(getID, opts[routesOpt])
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	limits, err := parseRateLimit(getID.limits, opts.get)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	// a link left behind by an earlier join of the endpoint
+	if ep := getID.endpointCopy(endID); ep != nil && ep.srcName != "" && ep.sandboxKey == "" {
+		if err := deleteLink(ep.srcName); err != nil {
+			log.Warnf("Unable to delete the stale link [ %s ] of endpoint [ %s ]: %s", ep.srcName, endID, err)
 		}
-		// L2 ipvlan needs an explicit IP for a default GW in the container netns
-		if ipVlanMode == ipVlanL2 {
-			res = &joinResponse{
-				InterfaceName: *ifname,
-				Gateway:       getID.gateway,
-			}
-			defer objectResponse(w, res)
+	}
+	netMode := getID.mode()
+	kind := getID.driverKind()
+	// Get the link for the master index (Example: the docker host eth iface)
+	hostEth, err := netlink.LinkByName(getID.ifaceOpt)
+	if err != nil {
+		errorResponsef(w, "parent interface [ %s ] of network [ %s ] not found: %s", getID.ifaceOpt, j.NetworkID, err)
+		return
+	}
+	child, err := newChildLink(getID, hostEth)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if limits.set() && child.Attrs().TxQLen == 0 {
+		child.Attrs().TxQLen = shapedTxQLen
+	}
+	// unique name while still on the common netns
+	if err := addEndpointLink(endID, child); err != nil {
+		log.Warnf("Orphaned links and netns mounts in `/var/run/docker/netns/` are removed by the collector every [ %s ]", driver.gcInterval)
+		errorResponsef(w, "failed to create the %s link for endpoint [ %s ] on [ %s ]: %s. Note: a parent "+
+			"interface cannot be linked to both macvlan and ipvlan simultaneously", kind, endID, getID.ifaceOpt, err)
+		return
+	}
+	childName := child.Attrs().Name
+	log.Infof("Created %s link: [ %s ] with a mode: [ %s ]", kind, childName, netMode)
+	// Set the netlink iface MTU, defaults to the MTU of the parent
+	linkMTU := getID.linkMTU(hostEth)
+	if err := netlink.LinkSetMTU(child, linkMTU); err != nil {
+		netlink.LinkDel(child)
+		errorResponsef(w, "unable to set the MTU [ %d ] for link [ %s ]: %s", linkMTU, childName, err)
+		return
+	}
+	var mac net.HardwareAddr
+	if ep := getID.endpointCopy(endID); ep != nil {
+		mac = ep.mac
+	}
+	if err := setChildMac(getID, child, mac); err != nil {
+		netlink.LinkDel(child)
+		errorResponsef(w, "unable to set the mac [ %s ] for link [ %s ]: %s", mac, childName, err)
+		return
+	}
+	// Bring the netlink iface up
+	if err := netlink.LinkSetUp(child); err != nil {
+		netlink.LinkDel(child)
+		errorResponsef(w, "unable to enable the %s link [ %s ]: %s", kind, childName, err)
+		return
+	}
+	if err := driver.joinedEndpoint(getID, endID, childName, child.Attrs().Index, j.SandboxKey, limits); err != nil {
+		netlink.LinkDel(child)
+		errorResponsef(w, "unable to persist the join of endpoint [ %s ]: %s", endID, err)
+		return
+	}
+	// SrcName gets renamed to DstPrefix on the container iface
+	ifname := &InterfaceName{
+		SrcName:   childName,
+		DstPrefix: containerEthPrefix,
+	}
+	res := &joinResponse{
+		InterfaceName: *ifname,
+	}
+	switch {
+	case getID.l2():
+		// L2 ipvlan and macvlan need an explicit IP for a default GW in the container netns
+		res.Gateway = getID.gateway
+		res.GatewayIPv6 = getID.gatewayV6
+		// a leased address uses the router the DHCP server handed out
+		if ep := getID.endpointCopy(endID); ep != nil && ep.lease != nil && ep.lease.Router != "" {
+			res.Gateway = ep.lease.Router
 		}
-
+	case getID.l3():
 		// ipvlan L3 mode doesnt need an IP for a default GW, just an iface dex.
-		if ipVlanMode == ipVlanL3 || ipVlanMode == ipVlanL3Routing {
-			res = &joinResponse{
-				InterfaceName: *ifname,
-			}
-			// Add a default route of only the interface inside the container
-			defaultRoute := &staticRoute{
-				Destination: defaultRoute,
-				RouteType:   types.CONNECTED,
-				NextHop:     "",
-			}
-			res.StaticRoutes = []*staticRoute{defaultRoute}
-		}
+		res.DisableGatewayService = true
+		res.StaticRoutes = getID.l3DefaultRoutes()
 	}
+	var epRoutes []*route
+	if ep := getID.endpointCopy(endID); ep != nil {
+		epRoutes = ep.routes
+	}
+	res.StaticRoutes = mergeRoutes(res.StaticRoutes, getID.routes, epRoutes, joinRoutes)
 	log.Debugf("Join response: %+v", res)
+	// libnetwork moves the link into the sandbox once it has the response
+	if limits.set() {
+		go shapeEndpoint(getID, endID)
+	}
+	go announceEndpoint(getID, endID)
 	// Send the response to libnetwork
 	objectResponse(w, res)
 	log.Debugf("Join endpoint %s:%s to %s", j.NetworkID, j.EndpointID, j.SandboxKey)
@@ -660,10 +1100,23 @@ type leave struct {
 func (driver *driver) leaveEndpoint(w http.ResponseWriter, r *http.Request) {
 	var l leave
 	if err := json.NewDecoder(r.Body).Decode(&l); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Leave request: %+v", &l)
+	if n, err := driver.getNetwork(l.NetworkID); err == nil {
+		if ep := n.endpointCopy(l.EndpointID); ep != nil {
+			removeIfb(ep)
+			if err := releaseLease(n, ep); err != nil {
+				log.Warnf("Unable to release the DHCP lease of endpoint [ %s ]: %s", ep.id, err)
+			}
+			n.setEndpointLease(ep.id, nil)
+		}
+		n.setEndpointSandbox(l.EndpointID, "", 0)
+		if err := driver.saveState(); err != nil {
+			log.Warnf("Unable to persist the leave of endpoint [ %s ]: %s", l.EndpointID, err)
+		}
+	}
 	emptyResponse(w)
 	log.Debugf("Leave %s:%s", l.NetworkID, l.EndpointID)
 }
@@ -676,15 +1129,19 @@ type newhost struct {
 func (driver *driver) discoverNew(w http.ResponseWriter, r *http.Request) {
 	var n newhost
 	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Discover new request:%v", n)
 	isself, _ := n.DiscoveryData["Self"].(bool)
 	Address, _ := n.DiscoveryData["Address"].(string)
-	if ipVlanMode == ipVlanL3Routing {
-		routing.DiscoverNew(isself, Address)
+	if driver.pluginConfig.mode == ipVlanL3Routing {
+		if err := routing.DiscoverNew(isself, Address); err != nil {
+			errorResponsef(w, "unable to add the discovered host [ %s ]: %s", Address, err)
+			return
+		}
 	}
+	emptyResponse(w)
 }
 
 type delhost struct {
@@ -695,34 +1152,109 @@ type delhost struct {
 func (driver *driver) discoverDelete(w http.ResponseWriter, r *http.Request) {
 	var d delhost
 	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
-		sendError(w, "Could not decode JSON encode payload", http.StatusBadRequest)
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
 		return
 	}
 	log.Debugf("Discover delete request:%v", d)
 	isself, _ := d.DiscoveryData["Self"].(bool)
 	Address, _ := d.DiscoveryData["Address"].(string)
-	if ipVlanMode == ipVlanL3Routing {
-		routing.DiscoverDelete(isself, Address)
+	if driver.pluginConfig.mode == ipVlanL3Routing {
+		if err := routing.DiscoverDelete(isself, Address); err != nil {
+			errorResponsef(w, "unable to remove the discovered host [ %s ]: %s", Address, err)
+			return
+		}
+	}
+	emptyResponse(w)
+}
+
+// parseNatOpts binds the -o nat and -o snat_ip options to a network. A
+// snat_ip implies nat. Only l3s slaves pass the host netfilter hooks, the
+// other ipvlan modes and macvlan bypass conntrack so only l3s networks can
+// be natted.
+func parseNatOpts(n *network, natOpt, snatOpt string) error {
+	if natOpt != "" {
+		nat, err := strconv.ParseBool(natOpt)
+		if err != nil {
+			return fmt.Errorf("invalid nat [ %s ], must be true or false", natOpt)
+		}
+		n.nat = nat
+	}
+	if snatOpt != "" {
+		ip := net.ParseIP(snatOpt)
+		if ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid snat_ip [ %s ], must be an IPv4 address", snatOpt)
+		}
+		n.snatIP = ip.To4()
+		n.nat = true
+	}
+	// only l3s slaves pass the host netfilter hooks, elsewhere conntrack
+	// never sees the return traffic
+	if n.nat && (n.macvlan() || n.mode() != ipVlanL3S) {
+		return fmt.Errorf("nat requires mode=%s, %s traffic bypasses the host netfilter hooks", ipVlanL3S, n.mode())
 	}
+	if n.nat && n.cidr == nil {
+		return fmt.Errorf("nat requires an IPv4 subnet")
+	}
+	return nil
 }
 
-func (driver *driver) natOut() error {
-	masquerade := []string{
+// natRule returns the POSTROUTING rule translating the network's IPv4 subnet
+// leaving its parent interface, masquerading unless a snat_ip is set
+func natRule(n *network) []string {
+	rule := []string{
 		"POSTROUTING", "-t", "nat",
-		"-s", driver.pluginConfig.containerSubnet.String(),
-		"-j", "MASQUERADE",
-	}
-	if _, err := iptables.Raw(
-		append([]string{"-C"}, masquerade...)...,
-	); err != nil {
-		incl := append([]string{"-I"}, masquerade...)
-		if output, err := iptables.Raw(incl...); err != nil {
-			return err
-		} else if len(output) > 0 {
-			return &iptables.ChainError{
-				Chain:  "POSTROUTING",
-				Output: output,
-			}
+		"-s", n.cidr.String(),
+		"-o", n.ifaceOpt,
+	}
+	if n.snatIP != nil {
+		return append(rule, "-j", "SNAT", "--to-source", n.snatIP.String())
+	}
+	return append(rule, "-j", "MASQUERADE")
+}
+
+// natOut installs the outbound NAT rule of a network if it is missing
+func natOut(n *network) error {
+	if !n.nat {
+		return nil
+	}
+	return insertRule(natRule(n))
+}
+
+// natOutDel removes the outbound NAT rule of a network if it is present
+func natOutDel(n *network) error {
+	if !n.nat {
+		return nil
+	}
+	return deleteRule(natRule(n))
+}
+
+// insertRule inserts an iptables rule, given as chain and match, unless it exists
+func insertRule(rule []string) error {
+	if _, err := iptables.Raw(append([]string{"-C"}, rule...)...); err == nil {
+		return nil
+	}
+	if output, err := iptables.Raw(append([]string{"-I"}, rule...)...); err != nil {
+		return err
+	} else if len(output) > 0 {
+		return &iptables.ChainError{
+			Chain:  rule[0],
+			Output: output,
+		}
+	}
+	return nil
+}
+
+// deleteRule removes an iptables rule if it is present
+func deleteRule(rule []string) error {
+	if _, err := iptables.Raw(append([]string{"-C"}, rule...)...); err != nil {
+		return nil
+	}
+	if output, err := iptables.Raw(append([]string{"-D"}, rule...)...); err != nil {
+		return err
+	} else if len(output) > 0 {
+		return &iptables.ChainError{
+			Chain:  rule[0],
+			Output: output,
 		}
 	}
 	return nil
@@ -0,0 +1,99 @@
+package ipvlan
+
+import (
+	"net"
+	"testing"
+)
+
+func TestL3DefaultRoutes(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("10.1.0.0/24")
+	_, v6, _ := net.ParseCIDR("fd00:1::/64")
+	tests := []struct {
+		cidr, cidrV6 *net.IPNet
+		want         []string
+	}{
+		{v4, nil, []string{defaultRoute}},
+		{nil, v6, []string{defaultRouteV6}},
+		{v4, v6, []string{defaultRoute, defaultRouteV6}},
+	}
+	for _, tt := range tests {
+		n := &network{cidr: tt.cidr, cidrV6: tt.cidrV6}
+		routes := n.l3DefaultRoutes()
+		if len(routes) != len(tt.want) {
+			t.Errorf("l3DefaultRoutes() for [ %v %v ] returned %d routes, want %v", tt.cidr, tt.cidrV6, len(routes), tt.want)
+			continue
+		}
+		for i, r := range routes {
+			if r.Destination != tt.want[i] || r.NextHop != "" {
+				t.Errorf("route %d is [ %s via %s ], want a connected [ %s ]", i, r.Destination, r.NextHop, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseNatOpts(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("10.1.0.0/24")
+	_, v6, _ := net.ParseCIDR("fd00:1::/64")
+	tests := []struct {
+		kind, mode string
+		cidr       *net.IPNet
+		nat, snat  string
+		wantNat    bool
+		wantSnat   string
+		wantErr    bool
+	}{
+		{driverKindIpvlan, ipVlanL3S, v4, "true", "", true, "", false},
+		{driverKindIpvlan, ipVlanL3S, v4, "", "192.0.2.10", true, "192.0.2.10", false},
+		{driverKindIpvlan, ipVlanL3S, v4, "false", "", false, "", false},
+		{driverKindIpvlan, ipVlanL3, v4, "false", "", false, "", false},
+		{driverKindIpvlan, ipVlanL3, v4, "true", "", false, "", true},
+		{driverKindIpvlan, ipVlanL3Routing, v4, "true", "", false, "", true},
+		{driverKindIpvlan, ipVlanL2, v4, "true", "", false, "", true},
+		{driverKindMacvlan, macvlanBridge, v4, "true", "", false, "", true},
+		{driverKindIpvlan, ipVlanL3S, v6, "true", "", false, "", true},
+		{driverKindIpvlan, ipVlanL3S, v4, "yes", "", false, "", true},
+		{driverKindIpvlan, ipVlanL3S, v4, "", "fd00::1", false, "", true},
+	}
+	for _, tt := range tests {
+		n := &network{kind: tt.kind, modeOpt: tt.mode}
+		if tt.cidr.IP.To4() != nil {
+			n.cidr = tt.cidr
+		} else {
+			n.cidrV6 = tt.cidr
+		}
+		err := parseNatOpts(n, tt.nat, tt.snat)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNatOpts(%s %s, %q, %q) error = %v, want error %t", tt.kind, tt.mode, tt.nat, tt.snat, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		var snat string
+		if n.snatIP != nil {
+			snat = n.snatIP.String()
+		}
+		if n.nat != tt.wantNat || snat != tt.wantSnat {
+			t.Errorf("parseNatOpts(%s %s, %q, %q) = nat %t snat %q, want %t %q", tt.kind, tt.mode, tt.nat, tt.snat, n.nat, snat, tt.wantNat, tt.wantSnat)
+		}
+	}
+}
+
+func TestTeardownNetworkReleasesEndpoints(t *testing.T) {
+	d := &driver{ownedLinks: map[string]bool{}}
+	addr := &net.IPNet{IP: net.ParseIP("192.168.50.2").To4(), Mask: net.CIDRMask(24, 32)}
+	n := &network{id: "net1", modeOpt: ipVlanL2, ifaceOpt: testParent, endpoints: endpointTable{
+		"created": {id: "created", addr: addr},
+		"leased":  {id: "leased", addr: addr, lease: &dhcpLease{Addr: "192.168.50.3/24", Server: "192.168.50.1"}},
+		"stale":   {id: "stale", sandboxKey: "/var/run/docker/netns/gone"},
+	}}
+	if err := d.teardownNetwork(n); err != nil {
+		t.Fatal(err)
+	}
+	if eps := n.endpointCopies(); len(eps) != 0 {
+		t.Errorf("teardownNetwork() left %d endpoints", len(eps))
+	}
+	if !n.deleting {
+		t.Errorf("teardownNetwork() did not mark the network as deleting")
+	}
+}
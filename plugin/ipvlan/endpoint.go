@@ -0,0 +1,274 @@
+package ipvlan
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// how long a task waits for libnetwork to move a joined link in
+	sandboxWait = 10 * time.Second
+	sandboxPoll = 100 * time.Millisecond
+)
+
+// errSandboxPending makes onSandboxLink poll again, the link has reached
+// the sandbox but libnetwork is not done configuring it
+var errSandboxPending = errors.New("sandbox link not configured")
+
+// linkStats are the interface counters reported by /proc/net/dev
+type linkStats struct {
+	RxBytes   uint64
+	RxPackets uint64
+	RxErrors  uint64
+	RxDropped uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxErrors  uint64
+	TxDropped uint64
+}
+
+// linkStatus is the live view of an endpoint's ipvlan link
+type linkStatus struct {
+	name  string
+	state string
+	mac   net.HardwareAddr
+	stats *linkStats
+}
+
+// joinedEndpoint records the link Join created for an endpoint, endpoints
+// the plugin has no record of are learned here
+func (driver *driver) joinedEndpoint(n *network, eid, srcName string, ifIndex int, sandboxKey string, limits rateLimit) error {
+	n.Lock()
+	ep, ok := n.endpoints[eid]
+	if !ok {
+		ep = &endpoint{id: eid}
+		n.endpoints[eid] = ep
+	}
+	ep.srcName = srcName
+	ep.ifIndex = ifIndex
+	ep.sandboxKey = sandboxKey
+	ep.limits = limits
+	n.Unlock()
+	return driver.saveState()
+}
+
+// endpointOperInfo returns the EndpointOperInfo data of an endpoint. The link
+// state and counters are best effort, they are read from the sandbox while
+// the endpoint is joined and from the default namespace otherwise.
+func endpointOperInfo(n *network, ep *endpoint) map[string]interface{} {
+	info := map[string]interface{}{
+		"link_name": ep.srcName,
+		"parent":    n.ifaceOpt,
+		"mode":      n.mode(),
+	}
+	if ep.addr != nil {
+		info["ipv4"] = ep.addr.String()
+	}
+	if ep.addrV6 != nil {
+		info["ipv6"] = ep.addrV6.String()
+	}
+	if ep.mac != nil {
+		info["mac"] = ep.mac.String()
+	}
+	if ep.sandboxKey != "" {
+		info["sandbox"] = ep.sandboxKey
+	}
+	if ep.sandboxKey != "" && ep.limits.EgressRate > 0 {
+		info["egress_rate"] = ep.limits.EgressRate
+	}
+	if ep.sandboxKey != "" && ep.limits.IngressRate > 0 {
+		info["ingress_rate"] = ep.limits.IngressRate
+	}
+	if ep.lease != nil {
+		info["dhcp_server"] = ep.lease.Server
+		info["lease_expires"] = ep.lease.Obtained.Add(ep.lease.Duration).Format(time.RFC3339)
+	}
+	if reason := n.degradedReason(); reason != "" {
+		info["degraded"] = fmt.Sprintf("parent interface %s", reason)
+	}
+	status, err := endpointLinkStatus(ep)
+	if err != nil {
+		info["link_state"] = "unknown"
+		info["link_error"] = err.Error()
+		return info
+	}
+	info["link_state"] = status.state
+	if status.name != ep.srcName {
+		info["container_link_name"] = status.name
+	}
+	// ipvlan children share the hardware address of their parent
+	if status.mac != nil {
+		info["mac"] = status.mac.String()
+	}
+	if s := status.stats; s != nil {
+		info["rx_bytes"] = s.RxBytes
+		info["rx_packets"] = s.RxPackets
+		info["rx_errors"] = s.RxErrors
+		info["rx_dropped"] = s.RxDropped
+		info["tx_bytes"] = s.TxBytes
+		info["tx_packets"] = s.TxPackets
+		info["tx_errors"] = s.TxErrors
+		info["tx_dropped"] = s.TxDropped
+	}
+	return info
+}
+
+// endpointLinkStatus inspects the child link of an endpoint
+func endpointLinkStatus(ep *endpoint) (*linkStatus, error) {
+	if ep.sandboxKey == "" {
+		if ep.srcName == "" {
+			return nil, fmt.Errorf("endpoint has not joined a container")
+		}
+		link, err := netlink.LinkByName(ep.srcName)
+		if err != nil {
+			return nil, fmt.Errorf("link [ %s ] not found: %s", ep.srcName, err)
+		}
+		return inspectLink(link), nil
+	}
+	var status *linkStatus
+	err := withNetns(ep.sandboxKey, func() error {
+		link, err := sandboxLink(ep)
+		if err != nil {
+			return err
+		}
+		status = inspectLink(link)
+		return nil
+	})
+	return status, err
+}
+
+// onSandboxLink runs fn in the sandbox of a joined endpoint once libnetwork
+// has moved the endpoint link in, Join returns before the move. Failures are
+// logged since the caller has already answered the Join.
+func onSandboxLink(ep *endpoint, task string, fn func(link netlink.Link) error) {
+	deadline := time.Now().Add(sandboxWait)
+	for {
+		moved := false
+		err := withNetns(ep.sandboxKey, func() error {
+			link, err := sandboxLink(ep)
+			if err != nil {
+				return nil
+			}
+			if err := fn(link); err != errSandboxPending {
+				moved = true
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			log.Warnf("Unable to apply the %s of endpoint [ %s ]: %s", task, ep.id, err)
+			return
+		}
+		if moved {
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Warnf("The link of endpoint [ %s ] did not reach the sandbox [ %s ] within [ %s ], skipping the %s",
+				ep.id, ep.sandboxKey, sandboxWait, task)
+			return
+		}
+		time.Sleep(sandboxPoll)
+	}
+}
+
+// sandboxLink finds the endpoint link inside its sandbox by the ifindex it
+// had on the host, or by its addresses when the index was reassigned
+func sandboxLink(ep *endpoint) (netlink.Link, error) {
+	if ep.ifIndex > 0 {
+		if link, err := netlink.LinkByIndex(ep.ifIndex); err == nil && childLink(link) {
+			return link, nil
+		}
+	}
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, err
+	}
+	for _, link := range links {
+		if !childLink(link) {
+			continue
+		}
+		addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if (ep.addr != nil && addr.IP.Equal(ep.addr.IP)) || (ep.addrV6 != nil && addr.IP.Equal(ep.addrV6.IP)) {
+				return link, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("endpoint link not found in the sandbox [ %s ]", ep.sandboxKey)
+}
+
+// childLink reports whether a link is an ipvlan or macvlan child
+func childLink(link netlink.Link) bool {
+	return link.Type() == driverKindIpvlan || link.Type() == driverKindMacvlan
+}
+
+// inspectLink reads the state and counters of a link in the network namespace
+// of the calling thread, counters are omitted when they cannot be read
+func inspectLink(link netlink.Link) *linkStatus {
+	attrs := link.Attrs()
+	status := &linkStatus{
+		name:  attrs.Name,
+		state: "down",
+		mac:   attrs.HardwareAddr,
+	}
+	if attrs.Flags&net.FlagUp != 0 {
+		status.state = "up"
+	}
+	status.stats, _ = readLinkStats(attrs.Name)
+	return status
+}
+
+// readLinkStats parses the counters of a link from /proc/net/dev of the
+// calling thread, the vendored netlink does not decode IFLA_STATS
+func readLinkStats(name string) (*linkStats, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/self/task/%d/net/dev", syscall.Gettid()))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		i := strings.Index(line, ":")
+		if i < 0 || strings.TrimSpace(line[:i]) != name {
+			continue
+		}
+		fields := strings.Fields(line[i+1:])
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("unexpected counters for link [ %s ]: %s", name, line)
+		}
+		var v [16]uint64
+		for k := range v {
+			if v[k], err = strconv.ParseUint(fields[k], 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		return &linkStats{
+			RxBytes:   v[0],
+			RxPackets: v[1],
+			RxErrors:  v[2],
+			RxDropped: v[3],
+			TxBytes:   v[8],
+			TxPackets: v[9],
+			TxErrors:  v[10],
+			TxDropped: v[11],
+		}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("link [ %s ] has no counters", name)
+}
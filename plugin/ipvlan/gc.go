@@ -0,0 +1,176 @@
+package ipvlan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	dockerNetnsPath = "/var/run/docker/netns"
+	gcGracePeriod   = 30 * time.Second
+)
+
+// collector reports, and with --gc-delete removes, the endpoint links and
+// sandbox netns bind mounts left behind by failed joins. An orphan is only
+// removed once it has been seen on two consecutive passes so that in flight
+// Join requests are never raced.
+type collector struct {
+	driver   *driver
+	interval time.Duration
+	dryRun   bool
+	suspects map[string]bool
+}
+
+func newCollector(d *driver, interval time.Duration, dryRun bool) *collector {
+	return &collector{
+		driver:   d,
+		interval: interval,
+		dryRun:   dryRun,
+		suspects: map[string]bool{},
+	}
+}
+
+// run collects at startup, confirming the first pass after a short grace
+// period, and then on every interval
+func (c *collector) run() {
+	c.collect()
+	time.Sleep(gcGracePeriod)
+	c.collect()
+	for range time.Tick(c.interval) {
+		c.collect()
+	}
+}
+
+func (c *collector) collect() {
+	suspects := map[string]bool{}
+	for _, link := range c.orphanedLinks() {
+		key := "link:" + link.Attrs().Name
+		suspects[key] = true
+		if !c.suspects[key] {
+			continue
+		}
+		if c.dryRun {
+			log.Infof("[dry-run] Would delete the orphaned endpoint link [ %s ]", link.Attrs().Name)
+			continue
+		}
+		log.Infof("Deleting the orphaned endpoint link [ %s ]", link.Attrs().Name)
+		if err := netlink.LinkDel(link); err != nil {
+			log.Errorf("Unable to delete the orphaned endpoint link [ %s ]: %s", link.Attrs().Name, err)
+		}
+	}
+	for _, path := range c.staleNetns() {
+		key := "netns:" + path
+		suspects[key] = true
+		if !c.suspects[key] {
+			continue
+		}
+		if c.dryRun {
+			log.Infof("[dry-run] Would unmount and remove the stale netns [ %s ]", path)
+			continue
+		}
+		log.Infof("Unmounting and removing the stale netns [ %s ]", path)
+		if err := syscall.Unmount(path, syscall.MNT_DETACH); err != nil && err != syscall.EINVAL {
+			log.Errorf("Unable to unmount the stale netns [ %s ]: %s", path, err)
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			log.Errorf("Unable to remove the stale netns [ %s ]: %s", path, err)
+		}
+	}
+	c.suspects = suspects
+}
+
+// orphanedLinks returns the endpoint links in the default namespace whose
+// parent is a managed host interface and that match no known endpoint. Only
+// links named by linkName are candidates, links an operator created by hand
+// on the same parent are never touched.
+func (c *collector) orphanedLinks() []netlink.Link {
+	parents := map[int]bool{}
+	known := map[string]bool{}
+	for _, n := range c.driver.getNetworks() {
+		if parent, err := netlink.LinkByName(n.ifaceOpt); err == nil {
+			parents[parent.Attrs().Index] = true
+		}
+		n.Lock()
+		for _, ep := range n.endpoints {
+			if ep.srcName != "" {
+				known[ep.srcName] = true
+			}
+		}
+		n.Unlock()
+	}
+	links, err := netlink.LinkList()
+	if err != nil {
+		log.Errorf("Unable to list the host links: %s", err)
+		return nil
+	}
+	var orphans []netlink.Link
+	for _, link := range links {
+		if (link.Type() != "ipvlan" && link.Type() != "macvlan") || !parents[link.Attrs().ParentIndex] {
+			continue
+		}
+		if !isLinkName(link.Attrs().Name) {
+			continue
+		}
+		if !known[link.Attrs().Name] {
+			orphans = append(orphans, link)
+		}
+	}
+	return orphans
+}
+
+// staleNetns returns the sandboxes recorded by joined endpoints whose netns
+// bind mount no process is running in. Namespaces of other drivers and of
+// sandboxes the plugin never joined are left alone. The processes are read
+// from /proc, the plugin must run in the host pid namespace.
+func (c *collector) staleNetns() []string {
+	sandboxes := map[string]bool{}
+	for _, n := range c.driver.getNetworks() {
+		for _, ep := range n.endpointCopies() {
+			if filepath.Dir(ep.sandboxKey) == dockerNetnsPath {
+				sandboxes[ep.sandboxKey] = true
+			}
+		}
+	}
+	if len(sandboxes) == 0 {
+		return nil
+	}
+	inUse := map[uint64]bool{}
+	procs, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		log.Errorf("Unable to read /proc: %s", err)
+		return nil
+	}
+	for _, p := range procs {
+		if _, err := strconv.Atoi(p.Name()); err != nil {
+			continue
+		}
+		if ino, ok := nsInode(filepath.Join("/proc", p.Name(), "ns", "net")); ok {
+			inUse[ino] = true
+		}
+	}
+	var stale []string
+	for path := range sandboxes {
+		if ino, ok := nsInode(path); ok && !inUse[ino] {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func nsInode(path string) (uint64, bool) {
+	var st syscall.Stat_t
+	if err := syscall.Stat(path, &st); err != nil {
+		return 0, false
+	}
+	return st.Ino, true
+}
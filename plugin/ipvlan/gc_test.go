@@ -0,0 +1,84 @@
+package ipvlan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+func TestStaleNetns(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("the stale netns test needs root to bind mount a namespace")
+	}
+	// only remove the directories the test creates
+	created := dockerNetnsPath
+	for dir := filepath.Dir(created); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		created = dir
+	}
+	if _, err := os.Stat(dockerNetnsPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(dockerNetnsPath, 0755); err != nil {
+			t.Skipf("unable to create %s: %s", dockerNetnsPath, err)
+		}
+		defer os.RemoveAll(created)
+	}
+	otherDir, err := ioutil.TempDir("", "ipvlan-netns")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(otherDir)
+
+	sandbox := func(name string) string {
+		return filepath.Join(dockerNetnsPath, "ipvtest-"+name)
+	}
+	touch := func(path string) {
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale, inUse, unjoined := sandbox("stale"), sandbox("inuse"), sandbox("unjoined")
+	other, missing := filepath.Join(otherDir, "stale"), sandbox("missing")
+	for _, path := range []string{stale, inUse, unjoined, other} {
+		touch(path)
+		defer os.Remove(path)
+	}
+	if err := syscall.Mount("/proc/self/ns/net", inUse, "", syscall.MS_BIND, ""); err != nil {
+		t.Skipf("unable to bind mount the test netns: %s", err)
+	}
+	defer syscall.Unmount(inUse, syscall.MNT_DETACH)
+
+	tests := []struct {
+		name      string
+		sandboxes []string
+		want      []string
+	}{
+		{"no endpoints", nil, nil},
+		{"stale sandbox", []string{stale}, []string{stale}},
+		{"sandbox in use", []string{inUse}, nil},
+		{"outside the docker netns path", []string{other}, nil},
+		{"removed sandbox", []string{missing}, nil},
+		{"mixed", []string{inUse, other, stale, missing}, []string{stale}},
+	}
+	for _, tt := range tests {
+		d, cleanup := newTestDriver(t, nil)
+		n := &network{id: "net1", modeOpt: ipVlanL2, endpoints: endpointTable{}}
+		for i, key := range tt.sandboxes {
+			n.addEndpoint(&endpoint{id: "ep" + strconv.Itoa(i), sandboxKey: key})
+		}
+		// an endpoint that never joined records no sandbox, the unjoined
+		// bind mount belongs to another driver and is never reported
+		n.addEndpoint(&endpoint{id: "created"})
+		d.addNetwork(n)
+		got := newCollector(d, gcGracePeriod, true).staleNetns()
+		cleanup()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: staleNetns() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
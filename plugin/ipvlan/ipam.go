@@ -0,0 +1,529 @@
+package ipvlan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	ipamReceiver       = "IpamDriver"
+	localAddressSpace  = "LocalDefault"
+	globalAddressSpace = "GlobalDefault"
+	// libnetwork pool data and address request option names
+	gatewayData        = "com.docker.network.gateway"
+	requestAddressType = "RequestAddressType"
+	// --ipam-opt exclude= addresses, ranges and subnets no container may use
+	excludeOpt = "exclude"
+	// bound on the addresses scanned for a free one, IPv6 pools are huge
+	maxAddressScan = 1 << 20
+)
+
+// addrRange is an inclusive range of addresses
+type addrRange struct {
+	first, last net.IP
+}
+
+func (r addrRange) contains(ip net.IP) bool {
+	return compareIP(ip, r.first) >= 0 && compareIP(ip, r.last) <= 0
+}
+
+func (r addrRange) String() string {
+	if r.first.Equal(r.last) {
+		return r.first.String()
+	}
+	return r.first.String() + "-" + r.last.String()
+}
+
+// pool is an address pool handed out by the plugin IPAM driver
+type pool struct {
+	id      string
+	space   string
+	subnet  *net.IPNet
+	ipRange *net.IPNet
+	// the LAN router detected on the parent, handed out as the gateway
+	gateway   net.IP
+	excluded  []addrRange
+	allocated map[string]bool
+}
+
+// ipam holds the pools of the plugin IPAM driver
+type ipam struct {
+	sync.Mutex
+	pools map[string]*pool
+}
+
+type ipamCapabilities struct {
+	RequiresMACAddress bool
+}
+
+type addressSpacesResponse struct {
+	LocalDefaultAddressSpace  string
+	GlobalDefaultAddressSpace string
+}
+
+type requestPool struct {
+	AddressSpace string
+	Pool         string
+	SubPool      string
+	Options      map[string]string
+	V6           bool
+}
+
+type requestPoolResponse struct {
+	PoolID string
+	Pool   string
+	Data   map[string]string
+}
+
+type releasePool struct {
+	PoolID string
+}
+
+type requestAddress struct {
+	PoolID  string
+	Address string
+	Options map[string]string
+}
+
+type requestAddressResponse struct {
+	Address string
+	Data    map[string]string
+}
+
+type releaseAddress struct {
+	PoolID  string
+	Address string
+}
+
+func (driver *driver) ipamCapabilities(w http.ResponseWriter, r *http.Request) {
+	objectResponse(w, &ipamCapabilities{})
+}
+
+func (driver *driver) getDefaultAddressSpaces(w http.ResponseWriter, r *http.Request) {
+	objectResponse(w, &addressSpacesResponse{
+		LocalDefaultAddressSpace:  localAddressSpace,
+		GlobalDefaultAddressSpace: globalAddressSpace,
+	})
+}
+
+func (driver *driver) requestPool(w http.ResponseWriter, r *http.Request) {
+	var req requestPool
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Request pool request: %+v", &req)
+	p, err := newPool(&req)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := driver.ipam.add(p); err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := driver.saveState(); err != nil {
+		driver.ipam.remove(p.id)
+		errorResponsef(w, "unable to persist the pool [ %s ]: %s", p.id, err)
+		return
+	}
+	res := &requestPoolResponse{
+		PoolID: p.id,
+		Pool:   p.subnet.String(),
+		Data:   map[string]string{},
+	}
+	// libnetwork takes the gateway from the pool data instead of requesting one
+	if p.gateway != nil {
+		res.Data[gatewayData] = (&net.IPNet{IP: p.gateway, Mask: p.subnet.Mask}).String()
+	}
+	log.Infof("Allocated the pool [ %s ] excluding [ %s ]", p.id, rangesString(p.excluded))
+	objectResponse(w, res)
+}
+
+func (driver *driver) releasePool(w http.ResponseWriter, r *http.Request) {
+	var req releasePool
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Release pool request: %+v", &req)
+	if driver.ipam.remove(req.PoolID) {
+		if err := driver.saveState(); err != nil {
+			errorResponsef(w, "unable to persist the release of the pool [ %s ]: %s", req.PoolID, err)
+			return
+		}
+		log.Infof("Released the pool [ %s ]", req.PoolID)
+	}
+	emptyResponse(w)
+}
+
+func (driver *driver) requestAddress(w http.ResponseWriter, r *http.Request) {
+	var req requestAddress
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Request address request: %+v", &req)
+	var want net.IP
+	if req.Address != "" {
+		if want = net.ParseIP(req.Address); want == nil {
+			errorResponsef(w, "invalid address [ %s ]", req.Address)
+			return
+		}
+	}
+	gateway := req.Options[requestAddressType] == gatewayData
+	addr, err := driver.ipam.allocate(req.PoolID, want, gateway)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	if err := driver.saveState(); err != nil {
+		driver.ipam.release(req.PoolID, addr.IP)
+		errorResponsef(w, "unable to persist the address [ %s ]: %s", addr.IP, err)
+		return
+	}
+	objectResponse(w, &requestAddressResponse{Address: addr.String(), Data: map[string]string{}})
+}
+
+func (driver *driver) releaseAddress(w http.ResponseWriter, r *http.Request) {
+	var req releaseAddress
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Release address request: %+v", &req)
+	ip := net.ParseIP(req.Address)
+	if ip == nil {
+		errorResponsef(w, "invalid address [ %s ]", req.Address)
+		return
+	}
+	if driver.ipam.release(req.PoolID, ip) {
+		if err := driver.saveState(); err != nil {
+			errorResponsef(w, "unable to persist the release of the address [ %s ]: %s", ip, err)
+			return
+		}
+	}
+	emptyResponse(w)
+}
+
+// newPool builds a pool from a RequestPool. Without --subnet the pool is the
+// subnet of the parent interface given by --ipam-opt host_iface or parent,
+// the plugin --host-interface otherwise. The host addresses on the subnet and
+// the LAN router are never handed out to containers.
+func newPool(req *requestPool) (*pool, error) {
+	space := req.AddressSpace
+	if space == "" {
+		space = localAddressSpace
+	}
+	iface := req.Options["host_iface"]
+	if iface == "" {
+		iface = req.Options["parent"]
+	}
+	if iface == "" {
+		iface = ipVlanEthIface
+	}
+	iface, err := resolveAutoIface(iface, "")
+	if err != nil {
+		return nil, err
+	}
+	family := netlink.FAMILY_V4
+	if req.V6 {
+		family = netlink.FAMILY_V6
+	}
+	hostAddrs, _ := ifaceAddrs(iface, family)
+	p := &pool{space: space, allocated: map[string]bool{}}
+	if req.Pool != "" {
+		_, subnet, err := net.ParseCIDR(req.Pool)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool [ %s ]: %s", req.Pool, err)
+		}
+		p.subnet = subnet
+	} else {
+		for _, addr := range hostAddrs {
+			if !addr.IP.IsLinkLocalUnicast() {
+				p.subnet = &net.IPNet{IP: addr.IP.Mask(addr.Mask), Mask: addr.Mask}
+				break
+			}
+		}
+		if p.subnet == nil {
+			return nil, fmt.Errorf("no --subnet given and the interface [ %s ] has no address to derive the pool from", iface)
+		}
+		log.Infof("Derived the pool [ %s ] from the interface [ %s ]", p.subnet, iface)
+	}
+	if req.V6 != (p.subnet.IP.To4() == nil) {
+		return nil, fmt.Errorf("pool [ %s ] does not match the requested address family", p.subnet)
+	}
+	p.subnet.IP = normalizeIP(p.subnet.IP)
+	if req.SubPool != "" {
+		_, ipRange, err := net.ParseCIDR(req.SubPool)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sub pool [ %s ]: %s", req.SubPool, err)
+		}
+		if !p.subnet.Contains(ipRange.IP) {
+			return nil, fmt.Errorf("sub pool [ %s ] is not in the pool [ %s ]", ipRange, p.subnet)
+		}
+		ipRange.IP = normalizeIP(ipRange.IP)
+		p.ipRange = ipRange
+	}
+	p.id = p.space + "/" + p.subnet.String()
+	if p.ipRange != nil {
+		p.id += "/" + p.ipRange.String()
+	}
+	for _, addr := range hostAddrs {
+		if p.subnet.Contains(addr.IP) {
+			p.excluded = append(p.excluded, addrRange{normalizeIP(addr.IP), normalizeIP(addr.IP)})
+		}
+	}
+	if gw := ifaceGateway(iface, family); gw != nil && p.subnet.Contains(gw) {
+		p.gateway = normalizeIP(gw)
+		p.excluded = append(p.excluded, addrRange{p.gateway, p.gateway})
+	}
+	excluded, err := parseExcluded(p.subnet, req.Options[excludeOpt])
+	if err != nil {
+		return nil, err
+	}
+	p.excluded = append(p.excluded, excluded...)
+	return p, nil
+}
+
+// ifaceAddrs returns the addresses of an interface in one family
+func ifaceAddrs(name string, family int) ([]netlink.Addr, error) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return netlink.AddrList(link, family)
+}
+
+// ifaceGateway returns the default route gateway reached through an interface
+func ifaceGateway(name string, family int) net.IP {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil
+	}
+	routes, err := netlink.RouteList(link, family)
+	if err != nil {
+		return nil
+	}
+	for _, r := range routes {
+		if r.Dst == nil && r.Gw != nil {
+			return r.Gw
+		}
+	}
+	return nil
+}
+
+// parseExcluded parses the exclude option, a comma separated list of
+// addresses, first-last ranges and subnets inside the pool
+func parseExcluded(subnet *net.IPNet, opt string) ([]addrRange, error) {
+	var ranges []addrRange
+	for _, item := range strings.Split(opt, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		var r addrRange
+		switch {
+		case strings.Contains(item, "/"):
+			_, cidr, err := net.ParseCIDR(item)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude [ %s ]: %s", item, err)
+			}
+			r.first, r.last = subnetBounds(cidr)
+		case strings.Contains(item, "-"):
+			parts := strings.SplitN(item, "-", 2)
+			r.first, r.last = net.ParseIP(strings.TrimSpace(parts[0])), net.ParseIP(strings.TrimSpace(parts[1]))
+		default:
+			r.first = net.ParseIP(item)
+			r.last = r.first
+		}
+		if r.first == nil || r.last == nil || compareIP(r.first, r.last) > 0 {
+			return nil, fmt.Errorf("invalid exclude [ %s ], must be an address, a first-last range or a subnet", item)
+		}
+		if !subnet.Contains(r.first) || !subnet.Contains(r.last) {
+			return nil, fmt.Errorf("exclude [ %s ] is not in the pool [ %s ]", item, subnet)
+		}
+		r.first, r.last = normalizeIP(r.first), normalizeIP(r.last)
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// add registers a pool, pools of one address space must not overlap
+func (m *ipam) add(p *pool) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.pools == nil {
+		m.pools = map[string]*pool{}
+	}
+	for _, other := range m.pools {
+		if other.space == p.space && (other.subnet.Contains(p.subnet.IP) || p.subnet.Contains(other.subnet.IP)) {
+			return fmt.Errorf("pool [ %s ] overlaps with the pool [ %s ] in the address space [ %s ]", p.subnet, other.subnet, p.space)
+		}
+	}
+	m.pools[p.id] = p
+	return nil
+}
+
+// remove releases a pool and its addresses, it reports whether the pool existed
+func (m *ipam) remove(id string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	_, ok := m.pools[id]
+	delete(m.pools, id)
+	return ok
+}
+
+// allocate reserves an address of a pool. A requested address only has to be
+// free, the gateway may also be an excluded address such as the LAN router.
+// Without a request the first free address of the sub pool is picked.
+func (m *ipam) allocate(id string, want net.IP, gateway bool) (*net.IPNet, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.pools[id]
+	if !ok {
+		return nil, fmt.Errorf("pool [ %s ] not found", id)
+	}
+	if want == nil && gateway && p.gateway != nil {
+		want = p.gateway
+	}
+	if want != nil {
+		want = normalizeIP(want)
+		if !p.subnet.Contains(want) {
+			return nil, fmt.Errorf("address [ %s ] is not in the pool [ %s ]", want, p.subnet)
+		}
+		if reservedAddr(p.subnet, want) {
+			return nil, fmt.Errorf("address [ %s ] is reserved in the pool [ %s ]", want, p.subnet)
+		}
+		if p.allocated[want.String()] {
+			return nil, fmt.Errorf("address [ %s ] is already allocated in the pool [ %s ]", want, p.subnet)
+		}
+		if !gateway && p.excludes(want) {
+			return nil, fmt.Errorf("address [ %s ] is excluded from the pool [ %s ]", want, p.subnet)
+		}
+		p.allocated[want.String()] = true
+		return &net.IPNet{IP: want, Mask: p.subnet.Mask}, nil
+	}
+	bounds := p.subnet
+	if p.ipRange != nil {
+		bounds = p.ipRange
+	}
+	first, last := subnetBounds(bounds)
+	ip := first
+	for i := 0; i < maxAddressScan && compareIP(ip, last) <= 0; i++ {
+		if !p.allocated[ip.String()] && !p.excludes(ip) && !reservedAddr(p.subnet, ip) {
+			p.allocated[ip.String()] = true
+			return &net.IPNet{IP: ip, Mask: p.subnet.Mask}, nil
+		}
+		ip = nextIP(ip)
+	}
+	return nil, fmt.Errorf("no free address left in the pool [ %s ]", id)
+}
+
+// release frees an address of a pool, it reports whether it was allocated
+func (m *ipam) release(id string, ip net.IP) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	p, ok := m.pools[id]
+	if !ok {
+		return false
+	}
+	key := normalizeIP(ip).String()
+	if !p.allocated[key] {
+		return false
+	}
+	delete(p.allocated, key)
+	return true
+}
+
+// excludes reports whether the pool of a subnet never hands out an address
+func (m *ipam) excludes(subnet *net.IPNet, ip net.IP) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	for _, p := range m.pools {
+		if p.subnet.String() == subnet.String() && p.excludes(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pool) excludes(ip net.IP) bool {
+	for _, r := range p.excluded {
+		if r.contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// reservedAddr reports whether an address is the network address, or the
+// broadcast address of an IPv4 subnet. /31 and /32 subnets use every address.
+func reservedAddr(subnet *net.IPNet, ip net.IP) bool {
+	if ones, bits := subnet.Mask.Size(); bits-ones < 2 {
+		return false
+	}
+	first, last := subnetBounds(subnet)
+	if ip.Equal(first) {
+		return true
+	}
+	return first.To4() != nil && ip.Equal(last)
+}
+
+// subnetBounds returns the first and last address of a subnet
+func subnetBounds(subnet *net.IPNet) (net.IP, net.IP) {
+	first := normalizeIP(subnet.IP.Mask(subnet.Mask))
+	last := make(net.IP, len(first))
+	mask := subnet.Mask
+	if len(mask) != len(first) {
+		mask = mask[len(mask)-len(first):]
+	}
+	for i := range first {
+		last[i] = first[i] | ^mask[i]
+	}
+	return first, last
+}
+
+// normalizeIP returns the 4 byte form of IPv4 addresses
+func normalizeIP(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip
+}
+
+func compareIP(a, b net.IP) int {
+	return bytes.Compare(a.To16(), b.To16())
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+func rangesString(ranges []addrRange) string {
+	s := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		s = append(s, r.String())
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,212 @@
+package ipvlan
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func testPool(t *testing.T, pool, subPool, exclude string) *pool {
+	p, err := newPool(&requestPool{
+		Pool:    pool,
+		SubPool: subPool,
+		V6:      strings.Contains(pool, ":"),
+		Options: map[string]string{"host_iface": testParent, excludeOpt: exclude},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestNewPool(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      requestPool
+		id       string
+		excluded string
+		wantErr  bool
+	}{
+		{
+			name: "pool",
+			req:  requestPool{Pool: "10.1.0.0/16"},
+			id:   "LocalDefault/10.1.0.0/16",
+		},
+		{
+			name: "sub pool",
+			req:  requestPool{AddressSpace: globalAddressSpace, Pool: "10.1.0.0/16", SubPool: "10.1.2.0/24"},
+			id:   "GlobalDefault/10.1.0.0/16/10.1.2.0/24",
+		},
+		{
+			name:     "excluded addresses",
+			req:      requestPool{Pool: "10.1.0.0/16", Options: map[string]string{excludeOpt: "10.1.0.1, 10.1.0.10-10.1.0.20,10.1.255.0/24"}},
+			id:       "LocalDefault/10.1.0.0/16",
+			excluded: "10.1.0.1,10.1.0.10-10.1.0.20,10.1.255.0-10.1.255.255",
+		},
+		{
+			name: "host bits",
+			req:  requestPool{Pool: "10.1.2.3/16"},
+			id:   "LocalDefault/10.1.0.0/16",
+		},
+		{
+			name:     "ipv6",
+			req:      requestPool{Pool: "2001:db8::/64", V6: true, Options: map[string]string{excludeOpt: "2001:db8::1"}},
+			id:       "LocalDefault/2001:db8::/64",
+			excluded: "2001:db8::1",
+		},
+		{name: "bad pool", req: requestPool{Pool: "10.1.0.0"}, wantErr: true},
+		{name: "ipv4 pool for v6", req: requestPool{Pool: "10.1.0.0/16", V6: true}, wantErr: true},
+		{name: "ipv6 pool for v4", req: requestPool{Pool: "2001:db8::/64"}, wantErr: true},
+		{name: "bad sub pool", req: requestPool{Pool: "10.1.0.0/16", SubPool: "10.1.2.0"}, wantErr: true},
+		{name: "sub pool outside", req: requestPool{Pool: "10.1.0.0/16", SubPool: "10.2.0.0/24"}, wantErr: true},
+		{name: "bad exclude", req: requestPool{Pool: "10.1.0.0/16", Options: map[string]string{excludeOpt: "10.1.0.20-10.1.0.10"}}, wantErr: true},
+		{name: "no subnet", req: requestPool{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if tt.req.Options == nil {
+			tt.req.Options = map[string]string{}
+		}
+		tt.req.Options["host_iface"] = testParent
+		p, err := newPool(&tt.req)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: newPool() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if p.id != tt.id {
+			t.Errorf("%s: newPool() id = %s, want %s", tt.name, p.id, tt.id)
+		}
+		if got := rangesString(p.excluded); got != tt.excluded {
+			t.Errorf("%s: newPool() excluded = %q, want %q", tt.name, got, tt.excluded)
+		}
+	}
+}
+
+func TestParseExcluded(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.1.0/24")
+	tests := []struct {
+		opt     string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{"192.168.1.1", "192.168.1.1", false},
+		{"192.168.1.10 - 192.168.1.20", "192.168.1.10-192.168.1.20", false},
+		{"192.168.1.5-192.168.1.5", "192.168.1.5", false},
+		{"192.168.1.128/25,192.168.1.2", "192.168.1.128-192.168.1.255,192.168.1.2", false},
+		{"192.168.1.300", "", true},
+		{"192.168.1.20-192.168.1.10", "", true},
+		{"192.168.1.10-", "", true},
+		{"192.168.1.0/33", "", true},
+		{"192.168.2.1", "", true},
+		{"192.168.1.250-192.168.2.5", "", true},
+		{"192.168.0.0/16", "", true},
+	}
+	for _, tt := range tests {
+		ranges, err := parseExcluded(subnet, tt.opt)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseExcluded(%q) error = %v, want error %t", tt.opt, err, tt.wantErr)
+			continue
+		}
+		if got := rangesString(ranges); got != tt.want {
+			t.Errorf("parseExcluded(%q) = %q, want %q", tt.opt, got, tt.want)
+		}
+	}
+}
+
+func TestIpamAllocate(t *testing.T) {
+	tests := []struct {
+		name                   string
+		pool, subPool, exclude string
+		want                   []string
+	}{
+		{"ipv4", "10.0.0.0/29", "", "10.0.0.2-10.0.0.3", []string{"10.0.0.1/29", "10.0.0.4/29", "10.0.0.5/29", "10.0.0.6/29"}},
+		{"sub pool", "10.0.0.0/24", "10.0.0.128/30", "10.0.0.129", []string{"10.0.0.128/24", "10.0.0.130/24", "10.0.0.131/24"}},
+		{"point to point", "10.0.0.0/31", "", "", []string{"10.0.0.0/31", "10.0.0.1/31"}},
+		{"ipv6", "2001:db8::/126", "", "", []string{"2001:db8::1/126", "2001:db8::2/126", "2001:db8::3/126"}},
+	}
+	for _, tt := range tests {
+		m := &ipam{}
+		p := testPool(t, tt.pool, tt.subPool, tt.exclude)
+		if err := m.add(p); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			got, err := m.allocate(p.id, nil, false)
+			if err != nil || got.String() != want {
+				t.Errorf("%s: allocate() = %v, %v, want %s", tt.name, got, err, want)
+			}
+		}
+		if got, err := m.allocate(p.id, nil, false); err == nil {
+			t.Errorf("%s: allocate() on a full pool = %s", tt.name, got)
+		}
+		first, _, _ := net.ParseCIDR(tt.want[0])
+		if !m.release(p.id, first) || m.release(p.id, first) {
+			t.Errorf("%s: release(%s) must only succeed once", tt.name, first)
+		}
+		if got, err := m.allocate(p.id, nil, false); err != nil || got.String() != tt.want[0] {
+			t.Errorf("%s: allocate() after release = %v, %v, want %s", tt.name, got, err, tt.want[0])
+		}
+	}
+}
+
+func TestIpamAllocateRequested(t *testing.T) {
+	m := &ipam{}
+	p := testPool(t, "10.0.0.0/24", "", "10.0.0.1,10.0.0.200-10.0.0.254")
+	if err := m.add(p); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ip      string
+		gateway bool
+		wantErr bool
+	}{
+		{"10.0.0.1", true, false},
+		{"10.0.0.1", true, true},
+		{"10.0.0.100", false, false},
+		{"10.0.0.100", false, true},
+		{"10.0.0.201", false, true},
+		{"10.0.0.202", true, false},
+		{"10.0.0.0", false, true},
+		{"10.0.0.255", true, true},
+		{"10.0.1.5", false, true},
+	}
+	for _, tt := range tests {
+		got, err := m.allocate(p.id, net.ParseIP(tt.ip), tt.gateway)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("allocate(%s, gateway %t) = %v, %v, want error %t", tt.ip, tt.gateway, got, err, tt.wantErr)
+		}
+	}
+	if _, err := m.allocate("LocalDefault/10.9.0.0/16", nil, false); err == nil {
+		t.Errorf("allocate() from an unknown pool did not fail")
+	}
+}
+
+func TestIpamPools(t *testing.T) {
+	m := &ipam{}
+	p := testPool(t, "10.0.0.0/16", "", "10.0.255.254")
+	if err := m.add(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.add(testPool(t, "10.0.5.0/24", "", "")); err == nil {
+		t.Errorf("add() accepted a pool inside an existing one")
+	}
+	if err := m.add(testPool(t, "10.0.0.0/8", "", "")); err == nil {
+		t.Errorf("add() accepted a pool around an existing one")
+	}
+	other, _ := newPool(&requestPool{AddressSpace: globalAddressSpace, Pool: "10.0.5.0/24", Options: map[string]string{"host_iface": testParent}})
+	if err := m.add(other); err != nil {
+		t.Errorf("add() rejected an overlapping pool of another address space: %s", err)
+	}
+	if !m.excludes(p.subnet, lastHostAddr(p.subnet)) || m.excludes(p.subnet, net.ParseIP("10.0.0.5")) {
+		t.Errorf("excludes() does not follow the exclude option of the pool")
+	}
+	if !m.remove(p.id) || m.remove(p.id) {
+		t.Errorf("remove(%s) must only succeed once", p.id)
+	}
+	if m.excludes(p.subnet, lastHostAddr(p.subnet)) {
+		t.Errorf("excludes() reports an address of a removed pool")
+	}
+}
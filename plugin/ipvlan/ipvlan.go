@@ -0,0 +1,168 @@
+package ipvlan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+)
+
+const (
+	// kernel ipvlan mode and port flags the vendored netlink does not know
+	ipvlanModeL3S   = netlink.IPVlanMode(2)
+	iflaIpvlanFlags = nl.IFLA_IPVLAN_MODE + 1
+
+	ipvlanFlagBridge  = "bridge"
+	ipvlanFlagPrivate = "private"
+	ipvlanFlagVepa    = "vepa"
+)
+
+var ipvlanFlags = map[string]uint16{
+	ipvlanFlagBridge:  0,
+	ipvlanFlagPrivate: 1,
+	ipvlanFlagVepa:    2,
+}
+
+// ipvlanLink is an ipvlan child carrying the port flags, it is created with
+// a raw netlink request since the vendored netlink cannot encode them
+type ipvlanLink struct {
+	netlink.IPVlan
+	flags uint16
+}
+
+// add creates the link, the link index is resolved like netlink.LinkAdd does
+func (l *ipvlanLink) add() error {
+	attrs := l.Attrs()
+	req := nl.NewNetlinkRequest(syscall.RTM_NEWLINK, syscall.NLM_F_CREATE|syscall.NLM_F_EXCL|syscall.NLM_F_ACK)
+	req.AddData(nl.NewIfInfomsg(syscall.AF_UNSPEC))
+	req.AddData(nl.NewRtAttr(syscall.IFLA_IFNAME, nl.ZeroTerminated(attrs.Name)))
+	req.AddData(nl.NewRtAttr(syscall.IFLA_LINK, nl.Uint32Attr(uint32(attrs.ParentIndex))))
+	if attrs.MTU > 0 {
+		req.AddData(nl.NewRtAttr(syscall.IFLA_MTU, nl.Uint32Attr(uint32(attrs.MTU))))
+	}
+	if attrs.TxQLen > 0 {
+		req.AddData(nl.NewRtAttr(syscall.IFLA_TXQLEN, nl.Uint32Attr(uint32(attrs.TxQLen))))
+	}
+	linkInfo := nl.NewRtAttr(syscall.IFLA_LINKINFO, nil)
+	nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_KIND, nl.NonZeroTerminated("ipvlan"))
+	data := nl.NewRtAttrChild(linkInfo, nl.IFLA_INFO_DATA, nil)
+	nl.NewRtAttrChild(data, nl.IFLA_IPVLAN_MODE, nl.Uint16Attr(uint16(l.Mode)))
+	nl.NewRtAttrChild(data, iflaIpvlanFlags, nl.Uint16Attr(l.flags))
+	req.AddData(linkInfo)
+	if _, err := req.Execute(syscall.NETLINK_ROUTE, 0); err != nil {
+		return err
+	}
+	link, err := netlink.LinkByName(attrs.Name)
+	if err != nil {
+		return err
+	}
+	attrs.Index = link.Attrs().Index
+	return nil
+}
+
+// addLink creates a child link, ipvlan links with port flags bypass netlink.LinkAdd
+func addLink(link netlink.Link) error {
+	if l, ok := link.(*ipvlanLink); ok {
+		return l.add()
+	}
+	return netlink.LinkAdd(link)
+}
+
+// portFlag returns the ipvlan port flag of the network, bridge when unset
+func (n *network) portFlag() string {
+	if n.ipvlanFlag == "" {
+		return ipvlanFlagBridge
+	}
+	return n.ipvlanFlag
+}
+
+// l3 reports whether the host routes the network subnets to the parent,
+// true for the ipvlan l3, l3s and l3routing modes
+func (n *network) l3() bool {
+	if n.macvlan() {
+		return false
+	}
+	switch n.mode() {
+	case ipVlanL3, ipVlanL3S, ipVlanL3Routing:
+		return true
+	}
+	return false
+}
+
+// newIPVlan returns an ipvlan child of the network, carrying the port flag when set
+func newIPVlan(n *network, attrs netlink.LinkAttrs, mode netlink.IPVlanMode) netlink.Link {
+	link := netlink.IPVlan{LinkAttrs: attrs, Mode: mode}
+	if n.ipvlanFlag == "" {
+		return &link
+	}
+	return &ipvlanLink{IPVlan: link, flags: ipvlanFlags[n.ipvlanFlag]}
+}
+
+// parseIpvlanFlag validates -o ipvlan_flag against the network and the
+// running kernel. l3s needs 4.9 and the port flags need 4.15.
+func parseIpvlanFlag(n *network, flag string) error {
+	if n.mode() == ipVlanL3S {
+		if err := requireKernel(4, 9, "ipvlan mode l3s"); err != nil {
+			return err
+		}
+	}
+	if flag == "" {
+		return nil
+	}
+	if n.macvlan() {
+		return fmt.Errorf("ipvlan_flag does not apply to macvlan networks, use -o mode instead")
+	}
+	if _, ok := ipvlanFlags[flag]; !ok {
+		return fmt.Errorf("unknown ipvlan_flag [ %s ], valid flags are [ %s | %s | %s ]",
+			flag, ipvlanFlagBridge, ipvlanFlagPrivate, ipvlanFlagVepa)
+	}
+	if err := requireKernel(4, 15, "ipvlan_flag"); err != nil {
+		return err
+	}
+	n.ipvlanFlag = flag
+	return nil
+}
+
+// requireKernel fails when the running kernel is older than major.minor
+func requireKernel(major, minor int, feature string) error {
+	var uts syscall.Utsname
+	if err := syscall.Uname(&uts); err != nil {
+		return err
+	}
+	var b []byte
+	for _, c := range uts.Release {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	release := string(b)
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("unable to parse the kernel release [ %s ]", release)
+	}
+	kmajor, err := leadingInt(parts[0])
+	if err != nil {
+		return fmt.Errorf("unable to parse the kernel release [ %s ]", release)
+	}
+	kminor, err := leadingInt(parts[1])
+	if err != nil {
+		return fmt.Errorf("unable to parse the kernel release [ %s ]", release)
+	}
+	if kmajor < major || (kmajor == major && kminor < minor) {
+		return fmt.Errorf("%s requires kernel %d.%d or newer, the running kernel is [ %s ]", feature, major, minor, release)
+	}
+	return nil
+}
+
+// leadingInt parses the digits a release component such as 15-rc1 starts with
+func leadingInt(s string) (int, error) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return strconv.Atoi(s[:i])
+}
@@ -0,0 +1,53 @@
+package ipvlan
+
+import "testing"
+
+func TestParseIpvlanFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       *network
+		flag    string
+		want    string
+		wantErr bool
+	}{
+		{name: "no flag", n: &network{modeOpt: ipVlanL2}},
+		{name: "l3s without a flag", n: &network{modeOpt: ipVlanL3S}},
+		{name: "bridge", n: &network{modeOpt: ipVlanL2}, flag: ipvlanFlagBridge, want: ipvlanFlagBridge},
+		{name: "private", n: &network{modeOpt: ipVlanL3}, flag: ipvlanFlagPrivate, want: ipvlanFlagPrivate},
+		{name: "vepa", n: &network{modeOpt: ipVlanL3S}, flag: ipvlanFlagVepa, want: ipvlanFlagVepa},
+		{name: "unknown flag", n: &network{modeOpt: ipVlanL2}, flag: "passthru", wantErr: true},
+		{name: "upper case flag", n: &network{modeOpt: ipVlanL2}, flag: "Private", wantErr: true},
+		{name: "macvlan", n: &network{kind: driverKindMacvlan, modeOpt: macvlanBridge}, flag: ipvlanFlagPrivate, wantErr: true},
+	}
+	if err := requireKernel(4, 15, "ipvlan_flag"); err != nil {
+		t.Skipf("the ipvlan flags are not supported: %s", err)
+	}
+	for _, tt := range tests {
+		err := parseIpvlanFlag(tt.n, tt.flag)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseIpvlanFlag() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.n.ipvlanFlag != tt.want {
+			t.Errorf("%s: parseIpvlanFlag() flag = %q, want %q", tt.name, tt.n.ipvlanFlag, tt.want)
+		}
+	}
+}
+
+func TestRequireKernel(t *testing.T) {
+	if err := requireKernel(2, 6, "test"); err != nil {
+		t.Errorf("requireKernel(2, 6) = %s, want nil", err)
+	}
+	if err := requireKernel(999, 0, "test"); err == nil {
+		t.Errorf("requireKernel(999, 0) did not fail")
+	}
+}
+
+func TestPortFlag(t *testing.T) {
+	if got := (&network{}).portFlag(); got != ipvlanFlagBridge {
+		t.Errorf("portFlag() without a flag = %s, want %s", got, ipvlanFlagBridge)
+	}
+	if got := (&network{ipvlanFlag: ipvlanFlagVepa}).portFlag(); got != ipvlanFlagVepa {
+		t.Errorf("portFlag() = %s, want %s", got, ipvlanFlagVepa)
+	}
+}
@@ -0,0 +1,87 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// kinds of child links a network is built from, one per driver socket
+	driverKindIpvlan  = "ipvlan"
+	driverKindMacvlan = "macvlan"
+
+	macvlanBridge   = "bridge"
+	macvlanPrivate  = "private"
+	macvlanVepa     = "vepa"
+	macvlanPassthru = "passthru"
+)
+
+var macvlanModes = map[string]netlink.MacvlanMode{
+	macvlanBridge:   netlink.MACVLAN_MODE_BRIDGE,
+	macvlanPrivate:  netlink.MACVLAN_MODE_PRIVATE,
+	macvlanVepa:     netlink.MACVLAN_MODE_VEPA,
+	macvlanPassthru: netlink.MACVLAN_MODE_PASSTHRU,
+}
+
+// parseMacvlanMode returns the netlink macvlan mode, bridge when unset
+func parseMacvlanMode(s string) (netlink.MacvlanMode, error) {
+	if s == "" {
+		s = macvlanBridge
+	}
+	mode, ok := macvlanModes[s]
+	if !ok {
+		return 0, fmt.Errorf("unknown macvlan mode [ %s ], valid modes are [ %s | %s | %s | %s ]",
+			s, macvlanBridge, macvlanPrivate, macvlanVepa, macvlanPassthru)
+	}
+	return mode, nil
+}
+
+// driverKind returns the kind of child links of the network, networks
+// persisted before macvlan support are ipvlan networks
+func (n *network) driverKind() string {
+	if n.kind == "" {
+		return driverKindIpvlan
+	}
+	return n.kind
+}
+
+func (n *network) macvlan() bool {
+	return n.kind == driverKindMacvlan
+}
+
+// l2 reports whether containers reach the subnet gateway over L2, true for
+// ipvlan l2 and every macvlan mode
+func (n *network) l2() bool {
+	return n.macvlan() || n.mode() == ipVlanL2
+}
+
+// newChildLink returns the unnamed child link of a network endpoint on parent
+func newChildLink(n *network, parent netlink.Link) (netlink.Link, error) {
+	attrs := netlink.LinkAttrs{
+		ParentIndex: parent.Attrs().Index,
+		TxQLen:      n.txQueueLen,
+	}
+	if n.macvlan() {
+		mode, err := parseMacvlanMode(n.mode())
+		if err != nil {
+			return nil, err
+		}
+		return &netlink.Macvlan{LinkAttrs: attrs, Mode: mode}, nil
+	}
+	mode, err := setIpVlanMode(n.mode())
+	if err != nil {
+		return nil, err
+	}
+	return newIPVlan(n, attrs, mode), nil
+}
+
+// setChildMac gives a macvlan child the stable mac derived from the endpoint
+// address, ipvlan children always share the mac of their parent
+func setChildMac(n *network, link netlink.Link, mac net.HardwareAddr) error {
+	if !n.macvlan() || mac == nil {
+		return nil
+	}
+	return netlink.LinkSetHardwareAddr(link, mac)
+}
@@ -0,0 +1,68 @@
+package ipvlan
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// withNetns runs fn inside the network namespace bind mounted at path, such
+// as a libnetwork SandboxKey. The namespace switch is confined to a locked OS
+// thread of a dedicated goroutine. If the thread cannot be switched back it
+// stays locked so the runtime discards it when the goroutine exits.
+func withNetns(path string, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		origin, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", syscall.Gettid()))
+		if err != nil {
+			runtime.UnlockOSThread()
+			errCh <- err
+			return
+		}
+		defer origin.Close()
+		target, err := os.Open(path)
+		if err != nil {
+			runtime.UnlockOSThread()
+			errCh <- fmt.Errorf("unable to open the network namespace [ %s ]: %s", path, err)
+			return
+		}
+		defer target.Close()
+		if err := setns(target.Fd()); err != nil {
+			runtime.UnlockOSThread()
+			errCh <- fmt.Errorf("unable to enter the network namespace [ %s ]: %s", path, err)
+			return
+		}
+		fnErr := fn()
+		if err := setns(origin.Fd()); err != nil {
+			errCh <- fmt.Errorf("unable to leave the network namespace [ %s ]: %s", path, err)
+			return
+		}
+		runtime.UnlockOSThread()
+		errCh <- fnErr
+	}()
+	return <-errCh
+}
+
+// setns syscall numbers, the syscall package does not export them
+var sysSetns = map[string]uintptr{
+	"386":     346,
+	"amd64":   308,
+	"arm":     375,
+	"arm64":   268,
+	"ppc64":   350,
+	"ppc64le": 350,
+	"s390x":   339,
+}
+
+func setns(fd uintptr) error {
+	trap, ok := sysSetns[runtime.GOARCH]
+	if !ok {
+		return fmt.Errorf("setns is not supported on [ %s ]", runtime.GOARCH)
+	}
+	if _, _, errno := syscall.RawSyscall(trap, fd, syscall.CLONE_NEWNET, 0); errno != 0 {
+		return errno
+	}
+	return nil
+}
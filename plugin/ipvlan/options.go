@@ -0,0 +1,413 @@
+package ipvlan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/libnetwork/types"
+)
+
+// optionType is the type of an option value
+type optionType string
+
+const (
+	typeString   optionType = "string"
+	typeBool     optionType = "bool"
+	typeInt      optionType = "int"
+	typeDuration optionType = "duration"
+	typeRate     optionType = "rate"
+	typeSize     optionType = "size"
+	typeIP       optionType = "ip"
+	typeList     optionType = "list"
+)
+
+// optionScope is where an option is accepted, endpoint options are read
+// from the endpoint and join requests
+type optionScope string
+
+const (
+	scopeNetwork  optionScope = "network"
+	scopeEndpoint optionScope = "endpoint"
+)
+
+// optionSpec declares a driver option. An empty Driver accepts the option
+// on both the ipvlan and the macvlan socket.
+type optionSpec struct {
+	Key     string
+	Type    optionType
+	Default string   `json:",omitempty"`
+	Allowed []string `json:",omitempty"`
+	Driver  string   `json:",omitempty"`
+	Scopes  []optionScope
+	Usage   string
+}
+
+// optionRule is a constraint between the options of a network
+type optionRule struct {
+	desc  string
+	check func(d *driver, kind string, o options) error
+}
+
+// options are the validated options of a request by key
+type options map[string]string
+
+func (o options) get(key string) string {
+	return o[key]
+}
+
+var (
+	networkScope  = []optionScope{scopeNetwork}
+	endpointScope = []optionScope{scopeNetwork, scopeEndpoint}
+)
+
+var optionSchema = []*optionSpec{
+	{Key: "mode", Type: typeString, Default: "--mode", Driver: driverKindIpvlan, Scopes: networkScope,
+		Allowed: []string{ipVlanL2, ipVlanL3, ipVlanL3S, ipVlanL3Routing}, Usage: "ipvlan mode of the network"},
+	{Key: "mode", Type: typeString, Default: macvlanBridge, Driver: driverKindMacvlan, Scopes: networkScope,
+		Allowed: []string{macvlanBridge, macvlanPrivate, macvlanVepa, macvlanPassthru}, Usage: "macvlan mode of the network"},
+	{Key: "host_iface", Type: typeString, Default: "--host-interface", Scopes: networkScope,
+		Usage: "parent interface, auto picks the default route interface"},
+	{Key: "parent", Type: typeString, Scopes: networkScope,
+		Usage: "parent interface, the base of the vlan_id sub-interface"},
+	{Key: "vlan_id", Type: typeInt, Scopes: networkScope,
+		Usage: "802.1Q sub-interface of the parent to attach to, created when missing"},
+	{Key: "mtu", Type: typeInt, Default: "--mtu", Scopes: networkScope,
+		Usage: "mtu of the container links, the parent mtu when unset"},
+	{Key: driverMTUOpt, Type: typeInt, Scopes: networkScope,
+		Usage: "mtu of the container links when mtu is not set"},
+	{Key: "txqueuelen", Type: typeInt, Default: "--txqueuelen", Scopes: networkScope,
+		Usage: "transmit queue length of the container links"},
+	{Key: "nat", Type: typeBool, Default: "false", Scopes: networkScope,
+		Usage: "masquerade the IPv4 subnet out of the parent"},
+	{Key: "snat_ip", Type: typeIP, Scopes: networkScope,
+		Usage: "source address of the outbound NAT instead of masquerading, implies nat"},
+	{Key: "host_shim", Type: typeBool, Default: "false", Scopes: networkScope,
+		Usage: "give the host a path to the l2 endpoints"},
+	{Key: "ipvlan_flag", Type: typeString, Default: ipvlanFlagBridge, Driver: driverKindIpvlan, Scopes: networkScope,
+		Allowed: []string{ipvlanFlagBridge, ipvlanFlagPrivate, ipvlanFlagVepa}, Usage: "ipvlan port isolation"},
+	{Key: "bond_slaves", Type: typeList, Scopes: networkScope,
+		Usage: "slaves of the bond the parent is built on"},
+	{Key: "bond_mode", Type: typeString, Default: "balance-rr", Scopes: networkScope,
+		Allowed: bondModeNames(), Usage: "mode of the bond built from bond_slaves"},
+	{Key: egressRateOpt, Type: typeRate, Scopes: endpointScope,
+		Usage: "egress bandwidth of each endpoint, e.g. 100mbit"},
+	{Key: egressBurstOpt, Type: typeSize, Scopes: endpointScope,
+		Usage: "egress burst of each endpoint, e.g. 32kb"},
+	{Key: ingressRateOpt, Type: typeRate, Scopes: endpointScope,
+		Usage: "ingress bandwidth of each endpoint"},
+	{Key: ingressBurstOpt, Type: typeSize, Scopes: endpointScope,
+		Usage: "ingress burst of each endpoint"},
+	{Key: routesOpt, Type: typeList, Scopes: endpointScope,
+		Usage: "static routes of the endpoints, <cidr> [via <ip>], comma separated"},
+	{Key: dhcpOpt, Type: typeBool, Default: "false", Scopes: networkScope,
+		Usage: "lease the IPv4 addresses from the parent LAN, requires --ipam-driver=null"},
+	{Key: dadOpt, Type: typeString, Default: dadOff, Scopes: networkScope,
+		Allowed: []string{dadOff, dadWarn, dadEnforce}, Usage: "duplicate address detection of the endpoint addresses"},
+	{Key: announceCountOpt, Type: typeInt, Default: strconv.Itoa(defaultAnnounceCount), Scopes: networkScope,
+		Usage: "gratuitous ARPs and neighbor advertisements sent at join, 0 disables them"},
+	{Key: announceIntervalOpt, Type: typeDuration, Default: defaultAnnounceInterval.String(), Scopes: networkScope,
+		Usage: "time between the join announcements"},
+}
+
+var optionRules = []optionRule{
+	{
+		desc: "mode=l3routing requires the plugin to run the routing manager, start it with --mode=l3routing",
+		check: func(d *driver, kind string, o options) error {
+			if d.optionMode(kind, o) == ipVlanL3Routing && d.routingManager == "" {
+				return fmt.Errorf("mode [ %s ] requires the routing manager, start the plugin with --mode=%s", ipVlanL3Routing, ipVlanL3Routing)
+			}
+			return nil
+		},
+	},
+	{
+		desc: fmt.Sprintf("nat and snat_ip require mode=%s, the other modes bypass the host netfilter hooks", ipVlanL3S),
+		check: func(d *driver, kind string, o options) error {
+			spec, _ := lookupOption("nat", kind)
+			key := "nat"
+			if o["snat_ip"] != "" {
+				// a snat_ip implies nat
+				key = "snat_ip"
+			} else if !spec.enabled(o["nat"]) {
+				return nil
+			}
+			if mode := d.optionMode(kind, o); kind == driverKindMacvlan || mode != ipVlanL3S {
+				return fmt.Errorf("option [ %s ] requires mode [ %s ], the mode is [ %s ]", key, ipVlanL3S, mode)
+			}
+			return nil
+		},
+	},
+	requireOption("bond_mode", "bond_slaves"),
+	requireOption(egressBurstOpt, egressRateOpt),
+	requireOption(ingressBurstOpt, ingressRateOpt),
+	requireL2(dhcpOpt),
+	requireL2(dadOpt),
+	requireL2("host_shim"),
+	requireL2(announceCountOpt),
+	requireL2(announceIntervalOpt),
+}
+
+// requireOption is the rule of an option only valid alongside another
+func requireOption(key, dep string) optionRule {
+	return optionRule{
+		desc: fmt.Sprintf("%s requires %s", key, dep),
+		check: func(d *driver, kind string, o options) error {
+			if o[key] != "" && o[dep] == "" {
+				return fmt.Errorf("option [ %s ] requires the option [ %s ]", key, dep)
+			}
+			return nil
+		},
+	}
+}
+
+// requireL2 is the rule of an option only valid on the l2 modes, where the
+// endpoints share the parent segment. Values that disable the feature are
+// accepted everywhere.
+func requireL2(key string) optionRule {
+	return optionRule{
+		desc: fmt.Sprintf("%s requires mode=%s or a macvlan network", key, ipVlanL2),
+		check: func(d *driver, kind string, o options) error {
+			spec, _ := lookupOption(key, kind)
+			if !spec.enabled(o[key]) || kind == driverKindMacvlan {
+				return nil
+			}
+			if mode := d.optionMode(kind, o); mode != ipVlanL2 {
+				return fmt.Errorf("option [ %s ] requires an l2 or macvlan network, the mode is [ %s ]", key, mode)
+			}
+			return nil
+		},
+	}
+}
+
+// optionMode returns the ipvlan mode a network would get, the plugin wide
+// --mode unless it is set
+func (d *driver) optionMode(kind string, o options) string {
+	if kind == driverKindMacvlan {
+		return o["mode"]
+	}
+	if mode := o["mode"]; mode != "" {
+		return mode
+	}
+	return d.pluginConfig.mode
+}
+
+func bondModeNames() []string {
+	var names []string
+	for name := range bondModes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupOption returns the spec of an option on a driver kind, or the spec
+// the other driver kind declares when the key is not valid on this one
+func lookupOption(key, kind string) (*optionSpec, bool) {
+	var other *optionSpec
+	for _, spec := range optionSchema {
+		if spec.Key != key {
+			continue
+		}
+		if spec.Driver == "" || spec.Driver == kind {
+			return spec, true
+		}
+		other = spec
+	}
+	return other, false
+}
+
+// parseOptions validates the options of a request against the schema. The
+// driver options are the libnetwork generic options, keys that carry a
+// namespace such as com.docker.network are left to their owners. Known top
+// level keys are read as well and lose to the generic ones.
+func parseOptions(scope optionScope, kind string, raw map[string]interface{}) (options, error) {
+	o := options{}
+	for key, val := range raw {
+		if key == genericOpt {
+			continue
+		}
+		if spec, ok := lookupOption(key, kind); ok && spec.accepts(scope) {
+			if err := o.set(spec, val); err != nil {
+				return nil, err
+			}
+		}
+	}
+	generic, ok := raw[genericOpt]
+	if !ok || generic == nil {
+		return o, nil
+	}
+	genericOpts, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, types.BadRequestErrorf("invalid generic options [ %v ]", generic)
+	}
+	for key, val := range genericOpts {
+		log.Debugf("Libnetwork Opts Sent: [ %s ] Value: [ %v ]", key, val)
+		spec, ok := lookupOption(key, kind)
+		switch {
+		case spec == nil && strings.Contains(key, "."):
+			continue
+		case spec == nil:
+			return nil, types.BadRequestErrorf("unknown option [ %s ], run 'ipvlan options' for the supported options", key)
+		case !ok:
+			return nil, types.BadRequestErrorf("option [ %s ] is only supported by the %s driver", key, spec.Driver)
+		case !spec.accepts(scope):
+			return nil, types.BadRequestErrorf("option [ %s ] can only be set on the network", key)
+		}
+		if err := o.set(spec, val); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// parseNetworkOptions validates the options of a network create, the rules
+// between options included
+func (d *driver) parseNetworkOptions(kind string, raw map[string]interface{}) (options, error) {
+	o, err := parseOptions(scopeNetwork, kind, raw)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range optionRules {
+		if err := rule.check(d, kind, o); err != nil {
+			return nil, types.BadRequestErrorf("%s", err)
+		}
+	}
+	return o, nil
+}
+
+// enabled reports whether a value turns the feature of an option on
+func (spec *optionSpec) enabled(v string) bool {
+	switch {
+	case v == "":
+		return false
+	case spec.Type == typeBool:
+		b, _ := strconv.ParseBool(v)
+		return b
+	case spec.Type == typeInt:
+		return v != "0"
+	}
+	return v != spec.Default
+}
+
+func (spec *optionSpec) accepts(scope optionScope) bool {
+	for _, s := range spec.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// set stores a value of an option once it matches the option type and its
+// allowed values
+func (o options) set(spec *optionSpec, val interface{}) error {
+	var s string
+	switch v := val.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case bool, float64, json.Number:
+		s = fmt.Sprint(v)
+	default:
+		return types.BadRequestErrorf("option [ %s ] must be passed as a string, got [ %v ]", spec.Key, val)
+	}
+	if s == "" {
+		return nil
+	}
+	if err := spec.Type.check(s); err != nil {
+		return types.BadRequestErrorf("invalid %s [ %s ] for option [ %s ]: %s", spec.Type, s, spec.Key, err)
+	}
+	if len(spec.Allowed) > 0 && !contains(spec.Allowed, s) {
+		return types.BadRequestErrorf("invalid value [ %s ] for option [ %s ], valid values are [ %s ]", s, spec.Key, strings.Join(spec.Allowed, " | "))
+	}
+	o[spec.Key] = s
+	return nil
+}
+
+// check validates the syntax of a value, the option parsers check its
+// meaning for the network
+func (t optionType) check(s string) error {
+	switch t {
+	case typeBool:
+		if _, err := strconv.ParseBool(s); err != nil {
+			return fmt.Errorf("must be true or false")
+		}
+	case typeInt:
+		if _, err := strconv.Atoi(s); err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+	case typeDuration:
+		if _, err := time.ParseDuration(s); err != nil {
+			return fmt.Errorf("must be a duration such as 500ms or 2s")
+		}
+	case typeRate:
+		_, err := parseRate(s)
+		return err
+	case typeSize:
+		_, err := parseSize(s)
+		return err
+	case typeIP:
+		if net.ParseIP(s) == nil {
+			return fmt.Errorf("must be an IP address")
+		}
+	}
+	return nil
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// printOptions writes the option schema as a table, or as JSON for tools
+func printOptions(w io.Writer, asJSON bool) error {
+	var rules []string
+	for _, rule := range optionRules {
+		rules = append(rules, rule.desc)
+	}
+	if asJSON {
+		return json.NewEncoder(w).Encode(struct {
+			Options []*optionSpec
+			Rules   []string
+		}{optionSchema, rules})
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTYPE\tDEFAULT\tDRIVER\tSCOPE\tVALUES\tDESCRIPTION")
+	for _, spec := range optionSchema {
+		driver := spec.Driver
+		if driver == "" {
+			driver = "all"
+		}
+		var scopes []string
+		for _, s := range spec.Scopes {
+			scopes = append(scopes, string(s))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", spec.Key, spec.Type, dash(spec.Default), driver,
+			strings.Join(scopes, ","), dash(strings.Join(spec.Allowed, "|")), spec.Usage)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\nRULES")
+	for _, rule := range rules {
+		fmt.Fprintf(w, "  %s\n", rule)
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+package ipvlan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func generic(opts map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{genericOpt: opts}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   optionScope
+		kind    string
+		raw     map[string]interface{}
+		want    options
+		wantErr bool
+	}{
+		{
+			name: "generic options",
+			kind: driverKindIpvlan,
+			raw:  generic(map[string]interface{}{"mode": "l3", "mtu": "1400", "nat": true, "host_iface": " eth1 "}),
+			want: options{"mode": "l3", "mtu": "1400", "nat": "true", "host_iface": "eth1"},
+		},
+		{
+			name: "json numbers",
+			kind: driverKindIpvlan,
+			raw:  generic(map[string]interface{}{"vlan_id": float64(20)}),
+			want: options{"vlan_id": "20"},
+		},
+		{
+			name: "top level keys lose to the generic ones",
+			kind: driverKindIpvlan,
+			raw:  map[string]interface{}{"mtu": "1500", "txqueuelen": "100", genericOpt: map[string]interface{}{"mtu": "9000"}},
+			want: options{"mtu": "9000", "txqueuelen": "100"},
+		},
+		{
+			name: "namespaced keys are left to their owners",
+			kind: driverKindIpvlan,
+			raw:  generic(map[string]interface{}{"com.docker.network.enable_ipv6": "true", "mode": "l2"}),
+			want: options{"mode": "l2"},
+		},
+		{
+			name: "empty values are unset",
+			kind: driverKindIpvlan,
+			raw:  generic(map[string]interface{}{"snat_ip": " "}),
+			want: options{},
+		},
+		{
+			name: "no generic options",
+			kind: driverKindMacvlan,
+			raw:  map[string]interface{}{},
+			want: options{},
+		},
+		{
+			name:  "endpoint options",
+			scope: scopeEndpoint,
+			kind:  driverKindIpvlan,
+			raw:   generic(map[string]interface{}{egressRateOpt: "10mbit", routesOpt: "10.1.0.0/16"}),
+			want:  options{egressRateOpt: "10mbit", routesOpt: "10.1.0.0/16"},
+		},
+		{name: "unknown option", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"mtus": "1500"}), wantErr: true},
+		{name: "other driver", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"mode": "vepa"}), wantErr: true},
+		{name: "ipvlan only", kind: driverKindMacvlan, raw: generic(map[string]interface{}{"ipvlan_flag": "private"}), wantErr: true},
+		{name: "network only", scope: scopeEndpoint, kind: driverKindIpvlan, raw: generic(map[string]interface{}{"mtu": "1500"}), wantErr: true},
+		{name: "bad int", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"mtu": "big"}), wantErr: true},
+		{name: "bad bool", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"nat": "yes"}), wantErr: true},
+		{name: "bad ip", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"snat_ip": "10.0.0"}), wantErr: true},
+		{name: "bad duration", kind: driverKindIpvlan, raw: generic(map[string]interface{}{announceIntervalOpt: "2"}), wantErr: true},
+		{name: "bad rate", kind: driverKindIpvlan, raw: generic(map[string]interface{}{egressRateOpt: "fast"}), wantErr: true},
+		{name: "not allowed", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"bond_mode": "round-robin"}), wantErr: true},
+		{name: "not a string", kind: driverKindIpvlan, raw: generic(map[string]interface{}{"mtu": []string{"1500"}}), wantErr: true},
+		{name: "bad generic", kind: driverKindIpvlan, raw: map[string]interface{}{genericOpt: "mode=l2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		scope := tt.scope
+		if scope == "" {
+			scope = scopeNetwork
+		}
+		got, err := parseOptions(scope, tt.kind, tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseOptions() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseOptions() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOptionRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          string
+		mode, manager string
+		opts          map[string]interface{}
+		wantErr       bool
+	}{
+		{name: "l3routing with the manager", kind: driverKindIpvlan, manager: "gobgp", opts: map[string]interface{}{"mode": ipVlanL3Routing}},
+		{name: "l3routing without the manager", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3Routing}, wantErr: true},
+		{name: "plugin mode l3routing", kind: driverKindIpvlan, mode: ipVlanL3Routing, opts: map[string]interface{}{}, wantErr: true},
+		{name: "nat on l3s", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3S, "nat": "true"}},
+		{name: "nat on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, "nat": "true"}, wantErr: true},
+		{name: "nat on macvlan", kind: driverKindMacvlan, opts: map[string]interface{}{"nat": "true"}, wantErr: true},
+		{name: "nat disabled", kind: driverKindMacvlan, opts: map[string]interface{}{"nat": "false"}},
+		{name: "snat_ip with nat", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3S, "nat": "true", "snat_ip": "192.0.2.1"}},
+		{name: "snat_ip implies nat", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3S, "snat_ip": "192.0.2.1"}},
+		{name: "snat_ip on l2", kind: driverKindIpvlan, opts: map[string]interface{}{"snat_ip": "192.0.2.1"}, wantErr: true},
+		{name: "snat_ip on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, "nat": "false", "snat_ip": "192.0.2.1"}, wantErr: true},
+		{name: "bond_mode without slaves", kind: driverKindIpvlan, opts: map[string]interface{}{"bond_mode": "802.3ad"}, wantErr: true},
+		{name: "bond_mode with slaves", kind: driverKindIpvlan, opts: map[string]interface{}{"bond_mode": "802.3ad", "bond_slaves": "eth1,eth2"}},
+		{name: "burst without rate", kind: driverKindIpvlan, opts: map[string]interface{}{egressBurstOpt: "32kb"}, wantErr: true},
+		{name: "ingress burst with rate", kind: driverKindIpvlan, opts: map[string]interface{}{ingressBurstOpt: "32kb", ingressRateOpt: "1mbit"}},
+		{name: "dhcp on l2", kind: driverKindIpvlan, opts: map[string]interface{}{dhcpOpt: "true"}},
+		{name: "dhcp on plugin mode l3", kind: driverKindIpvlan, mode: ipVlanL3, opts: map[string]interface{}{dhcpOpt: "true"}, wantErr: true},
+		{name: "dhcp on macvlan", kind: driverKindMacvlan, opts: map[string]interface{}{dhcpOpt: "true"}},
+		{name: "dad off on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, dadOpt: dadOff}},
+		{name: "dad on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, dadOpt: dadWarn}, wantErr: true},
+		{name: "host_shim on l3s", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3S, "host_shim": "true"}, wantErr: true},
+		{name: "no announcements on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, announceCountOpt: "0"}},
+		{name: "announcements on l3", kind: driverKindIpvlan, opts: map[string]interface{}{"mode": ipVlanL3, announceCountOpt: "3"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		d := &driver{pluginConfig: pluginConfig{mode: ipVlanL2, routingManager: tt.manager}}
+		if tt.mode != "" {
+			d.mode = tt.mode
+		}
+		_, err := d.parseNetworkOptions(tt.kind, generic(tt.opts))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseNetworkOptions() error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestOptionEnabled(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       bool
+	}{
+		{"nat", "", false},
+		{"nat", "false", false},
+		{"nat", "true", true},
+		{announceCountOpt, "0", false},
+		{announceCountOpt, "2", true},
+		{dadOpt, dadOff, false},
+		{dadOpt, dadEnforce, true},
+	}
+	for _, tt := range tests {
+		spec, _ := lookupOption(tt.key, driverKindIpvlan)
+		if got := spec.enabled(tt.value); got != tt.want {
+			t.Errorf("enabled(%s=%q) = %t, want %t", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
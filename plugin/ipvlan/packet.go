@@ -0,0 +1,161 @@
+package ipvlan
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+	"time"
+)
+
+const (
+	ethPIPv6 = 0x86dd
+
+	arpRequest = 1
+	arpReply   = 2
+
+	icmpv6NeighborSolicit = 135
+	icmpv6NeighborAdvert  = 136
+	// neighbor advertisement override flag and target link-layer address option
+	ndpOverride     = 0x20
+	ndpTargetLLAddr = 2
+)
+
+var (
+	broadcastMAC = net.HardwareAddr{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	allNodes     = net.ParseIP("ff02::1")
+)
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// packetSocket opens a datagram packet socket for one ethertype on a link,
+// the kernel builds the ethernet header from the destination mac
+func packetSocket(ifIndex int, proto uint16, timeout time.Duration) (int, error) {
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM, int(htons(proto)))
+	if err != nil {
+		return -1, err
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: htons(proto), Ifindex: ifIndex}); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	tv := syscall.NsecToTimeval(int64(timeout))
+	if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return fd, nil
+}
+
+// sendPacket sends a packet of the ethertype to a mac on the link
+func sendPacket(fd, ifIndex int, proto uint16, dst net.HardwareAddr, pkt []byte) error {
+	sa := &syscall.SockaddrLinklayer{Protocol: htons(proto), Ifindex: ifIndex, Halen: 6}
+	copy(sa.Addr[:], dst)
+	return syscall.Sendto(fd, pkt, 0, sa)
+}
+
+// recvPacket reads a packet received from another node, the packets the
+// socket sees leaving the link are skipped. A zero length marks a timeout.
+func recvPacket(fd int, buf []byte) (int, net.HardwareAddr, error) {
+	for {
+		n, from, err := syscall.Recvfrom(fd, buf, 0)
+		if err == syscall.EAGAIN || err == syscall.EINTR {
+			return 0, nil, nil
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		ll, ok := from.(*syscall.SockaddrLinklayer)
+		if !ok || ll.Pkttype == syscall.PACKET_OUTGOING {
+			continue
+		}
+		var mac net.HardwareAddr
+		if ll.Halen == 6 {
+			mac = net.HardwareAddr(append([]byte(nil), ll.Addr[:6]...))
+		}
+		return n, mac, nil
+	}
+}
+
+// arpPacket builds an ethernet IPv4 ARP message
+func arpPacket(op uint16, sha net.HardwareAddr, spa net.IP, tha net.HardwareAddr, tpa net.IP) []byte {
+	b := make([]byte, 28)
+	binary.BigEndian.PutUint16(b[0:], 1)
+	binary.BigEndian.PutUint16(b[2:], syscall.ETH_P_IP)
+	b[4], b[5] = 6, 4
+	binary.BigEndian.PutUint16(b[6:], op)
+	copy(b[8:14], sha)
+	copy(b[14:18], spa.To4())
+	copy(b[18:24], tha)
+	copy(b[24:28], tpa.To4())
+	return b
+}
+
+// ndpPacket builds an IPv6 neighbor solicitation or advertisement for the
+// target. A link-layer address is carried as the target address option.
+func ndpPacket(icmpType byte, src, dst, target net.IP, flags byte, lladdr net.HardwareAddr) []byte {
+	icmp := make([]byte, 24)
+	icmp[0] = icmpType
+	icmp[4] = flags
+	copy(icmp[8:], target.To16())
+	if lladdr != nil {
+		icmp = append(icmp, ndpTargetLLAddr, 1)
+		icmp = append(icmp, lladdr...)
+	}
+	pkt := make([]byte, 40, 40+len(icmp))
+	pkt[0] = 0x60
+	binary.BigEndian.PutUint16(pkt[4:], uint16(len(icmp)))
+	pkt[6] = syscall.IPPROTO_ICMPV6
+	// neighbor discovery messages must arrive with the maximum hop limit
+	pkt[7] = 255
+	copy(pkt[8:24], src.To16())
+	copy(pkt[24:40], dst.To16())
+	// the ICMPv6 checksum covers a pseudo header of the addresses, the
+	// length and the next header
+	pseudo := make([]byte, 40)
+	copy(pseudo, pkt[8:40])
+	binary.BigEndian.PutUint32(pseudo[32:], uint32(len(icmp)))
+	pseudo[39] = syscall.IPPROTO_ICMPV6
+	binary.BigEndian.PutUint16(icmp[2:], checksum(append(pseudo, icmp...)))
+	return append(pkt, icmp...)
+}
+
+// parseNdp returns the type and target of a neighbor discovery packet
+func parseNdp(pkt []byte) (byte, net.IP, net.IP) {
+	if len(pkt) < 64 || pkt[0]>>4 != 6 || pkt[6] != syscall.IPPROTO_ICMPV6 {
+		return 0, nil, nil
+	}
+	t := pkt[40]
+	if t != icmpv6NeighborSolicit && t != icmpv6NeighborAdvert {
+		return 0, nil, nil
+	}
+	return t, net.IP(pkt[8:24]), net.IP(pkt[48:64])
+}
+
+// solicitedNode returns the solicited-node multicast group of an address
+func solicitedNode(ip net.IP) net.IP {
+	group := net.ParseIP("ff02::1:ff00:0")
+	copy(group[13:], ip.To16()[13:])
+	return group
+}
+
+// multicastMAC returns the ethernet mac of an IPv6 multicast group
+func multicastMAC(group net.IP) net.HardwareAddr {
+	return net.HardwareAddr{0x33, 0x33, group[12], group[13], group[14], group[15]}
+}
+
+// checksum is the internet checksum of b
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum > 0xffff {
+		sum = sum>>16 + sum&0xffff
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,170 @@
+package ipvlan
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want uint16
+	}{
+		// the example of RFC 1071
+		{[]byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6, 0xf7}, 0x220d},
+		{[]byte{0x00, 0x01, 0xf2, 0x03, 0xf4, 0xf5, 0xf6}, 0x2304},
+		{[]byte{0xff, 0xff}, 0x0000},
+		{nil, 0xffff},
+		// an IPv4 header with its checksum zeroed
+		{[]byte{0x45, 0x00, 0x00, 0x73, 0x00, 0x00, 0x40, 0x00, 0x40, 0x11, 0x00, 0x00,
+			0xc0, 0xa8, 0x00, 0x01, 0xc0, 0xa8, 0x00, 0xc7}, 0xb861},
+	}
+	for _, tt := range tests {
+		if got := checksum(tt.b); got != tt.want {
+			t.Errorf("checksum(% x) = %#04x, want %#04x", tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestArpPacket(t *testing.T) {
+	mac, _ := net.ParseMAC("7a:42:0a:00:00:05")
+	ip := net.ParseIP("10.0.0.5")
+	tests := []struct {
+		name string
+		op   uint16
+		tha  net.HardwareAddr
+		tpa  net.IP
+	}{
+		{"probe", arpRequest, nil, ip},
+		{"gratuitous reply", arpReply, broadcastMAC, ip},
+		{"reply", arpReply, net.HardwareAddr{2, 0, 0, 0, 0, 1}, net.ParseIP("10.0.0.1")},
+	}
+	for _, tt := range tests {
+		b := arpPacket(tt.op, mac, ip, tt.tha, tt.tpa)
+		if len(b) != 28 {
+			t.Fatalf("%s: length = %d, want 28", tt.name, len(b))
+		}
+		if htype, ptype := binary.BigEndian.Uint16(b[0:]), binary.BigEndian.Uint16(b[2:]); htype != 1 || ptype != syscall.ETH_P_IP || b[4] != 6 || b[5] != 4 {
+			t.Errorf("%s: header = % x, want ethernet and IPv4", tt.name, b[:6])
+		}
+		if op := binary.BigEndian.Uint16(b[6:]); op != tt.op {
+			t.Errorf("%s: op = %d, want %d", tt.name, op, tt.op)
+		}
+		tha := tt.tha
+		if tha == nil {
+			tha = make(net.HardwareAddr, 6)
+		}
+		if !bytes.Equal(b[8:14], mac) || !net.IP(b[14:18]).Equal(ip) || !bytes.Equal(b[18:24], tha) || !net.IP(b[24:28]).Equal(tt.tpa) {
+			t.Errorf("%s: addresses = % x", tt.name, b[8:])
+		}
+	}
+}
+
+// icmpv6Valid verifies the ICMPv6 checksum of an IPv6 packet over its pseudo header
+func icmpv6Valid(pkt []byte) bool {
+	icmp := pkt[40:]
+	pseudo := make([]byte, 40, 40+len(icmp))
+	copy(pseudo, pkt[8:40])
+	binary.BigEndian.PutUint32(pseudo[32:], uint32(len(icmp)))
+	pseudo[39] = syscall.IPPROTO_ICMPV6
+	return checksum(append(pseudo, icmp...)) == 0
+}
+
+func TestNdpPacket(t *testing.T) {
+	mac, _ := net.ParseMAC("7a:42:00:00:00:05")
+	target := net.ParseIP("2001:db8::5")
+	tests := []struct {
+		name     string
+		icmpType byte
+		src, dst net.IP
+		flags    byte
+		lladdr   net.HardwareAddr
+	}{
+		{"dad probe", icmpv6NeighborSolicit, net.IPv6unspecified, solicitedNode(target), 0, nil},
+		{"unsolicited advertisement", icmpv6NeighborAdvert, target, allNodes, ndpOverride, mac},
+	}
+	for _, tt := range tests {
+		pkt := ndpPacket(tt.icmpType, tt.src, tt.dst, target, tt.flags, tt.lladdr)
+		want := 40 + 24
+		if tt.lladdr != nil {
+			want += 8
+		}
+		if len(pkt) != want {
+			t.Fatalf("%s: length = %d, want %d", tt.name, len(pkt), want)
+		}
+		if pkt[0]>>4 != 6 || pkt[6] != syscall.IPPROTO_ICMPV6 || pkt[7] != 255 {
+			t.Errorf("%s: IPv6 header = % x", tt.name, pkt[:8])
+		}
+		if l := binary.BigEndian.Uint16(pkt[4:]); int(l) != want-40 {
+			t.Errorf("%s: payload length = %d, want %d", tt.name, l, want-40)
+		}
+		if !icmpv6Valid(pkt) {
+			t.Errorf("%s: bad ICMPv6 checksum %#04x", tt.name, binary.BigEndian.Uint16(pkt[42:]))
+		}
+		if pkt[44] != tt.flags {
+			t.Errorf("%s: flags = %#x, want %#x", tt.name, pkt[44], tt.flags)
+		}
+		if tt.lladdr != nil && (pkt[64] != ndpTargetLLAddr || pkt[65] != 1 || !bytes.Equal(pkt[66:72], tt.lladdr)) {
+			t.Errorf("%s: link-layer address option = % x", tt.name, pkt[64:])
+		}
+		icmpType, src, got := parseNdp(pkt)
+		if icmpType != tt.icmpType || !src.Equal(tt.src) || !got.Equal(target) {
+			t.Errorf("%s: parseNdp() = %d %s %s, want %d %s %s", tt.name, icmpType, src, got, tt.icmpType, tt.src, target)
+		}
+	}
+}
+
+func TestParseNdp(t *testing.T) {
+	target := net.ParseIP("2001:db8::5")
+	na := ndpPacket(icmpv6NeighborAdvert, target, allNodes, target, ndpOverride, nil)
+	echo := ndpPacket(128, target, allNodes, target, 0, nil)
+	udp := append([]byte(nil), na...)
+	udp[6] = syscall.IPPROTO_UDP
+	ipv4 := append([]byte(nil), na...)
+	ipv4[0] = 0x45
+	tests := []struct {
+		name     string
+		pkt      []byte
+		icmpType byte
+	}{
+		{"advertisement", na, icmpv6NeighborAdvert},
+		{"truncated", na[:63], 0},
+		{"echo request", echo, 0},
+		{"udp", udp, 0},
+		{"ipv4", ipv4, 0},
+		{"empty", nil, 0},
+	}
+	for _, tt := range tests {
+		icmpType, src, got := parseNdp(tt.pkt)
+		if icmpType != tt.icmpType {
+			t.Errorf("%s: parseNdp() type = %d, want %d", tt.name, icmpType, tt.icmpType)
+		}
+		if tt.icmpType == 0 && (src != nil || got != nil) {
+			t.Errorf("%s: parseNdp() = %s %s, want no addresses", tt.name, src, got)
+		}
+	}
+}
+
+func TestSolicitedNode(t *testing.T) {
+	tests := []struct {
+		ip, group, mac string
+	}{
+		{"2001:db8::5", "ff02::1:ff00:5", "33:33:ff:00:00:05"},
+		{"fe80::7842:aff:fe12:3456", "ff02::1:ff12:3456", "33:33:ff:12:34:56"},
+	}
+	for _, tt := range tests {
+		group := solicitedNode(net.ParseIP(tt.ip))
+		if !group.Equal(net.ParseIP(tt.group)) {
+			t.Errorf("solicitedNode(%s) = %s, want %s", tt.ip, group, tt.group)
+		}
+		if mac := multicastMAC(group).String(); mac != tt.mac {
+			t.Errorf("multicastMAC(%s) = %s, want %s", group, mac, tt.mac)
+		}
+	}
+	if mac := multicastMAC(allNodes).String(); mac != "33:33:00:00:00:01" {
+		t.Errorf("multicastMAC(%s) = %s, want 33:33:00:00:00:01", allNodes, mac)
+	}
+}
@@ -0,0 +1,298 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// host_iface value selecting the parent from the host routing table
+	autoIfaceOpt = "auto"
+	bondMiimon   = 100
+)
+
+// kernel IFLA_BOND_MODE values, the vendored netlink constants do not match them
+var bondModes = map[string]int{
+	"balance-rr":    0,
+	"active-backup": 1,
+	"balance-xor":   2,
+	"broadcast":     3,
+	"802.3ad":       4,
+	"balance-tlb":   5,
+	"balance-alb":   6,
+}
+
+// resolveAutoIface replaces host_iface=auto with the interface whose subnet
+// contains the gateway, or else with the interface holding the default route
+func resolveAutoIface(name, gateway string) (string, error) {
+	if name != autoIfaceOpt {
+		return name, nil
+	}
+	if gw := net.ParseIP(gateway); gw != nil {
+		if iface := gatewayIface(gw); iface != "" {
+			log.Infof("Detected the parent interface [ %s ] on the subnet of the gateway [ %s ]", iface, gw)
+			return iface, nil
+		}
+	}
+	iface, err := defaultRouteIface()
+	if err != nil {
+		return "", err
+	}
+	log.Infof("Detected the parent interface [ %s ] holding the default route", iface)
+	return iface, nil
+}
+
+// gatewayIface returns the host interface with an address on the subnet of gw
+func gatewayIface(gw net.IP) string {
+	family := netlink.FAMILY_V4
+	if gw.To4() == nil {
+		family = netlink.FAMILY_V6
+	}
+	links, err := netlink.LinkList()
+	if err != nil {
+		return ""
+	}
+	for _, link := range links {
+		// skip loopback and the plugin's own child links such as host shims
+		if link.Attrs().Flags&net.FlagLoopback != 0 || link.Type() == "ipvlan" || link.Type() == "macvlan" {
+			continue
+		}
+		addrs, err := netlink.AddrList(link, family)
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if addr.IPNet.Contains(gw) {
+				return link.Attrs().Name
+			}
+		}
+	}
+	return ""
+}
+
+// defaultRouteIface returns the interface of the IPv4 or else IPv6 default route
+func defaultRouteIface() (string, error) {
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		routes, err := netlink.RouteList(nil, family)
+		if err != nil {
+			return "", err
+		}
+		for _, r := range routes {
+			if r.Dst != nil || r.LinkIndex <= 0 {
+				continue
+			}
+			link, err := netlink.LinkByIndex(r.LinkIndex)
+			if err != nil {
+				continue
+			}
+			return link.Attrs().Name, nil
+		}
+	}
+	return "", fmt.Errorf("host_iface=auto found no interface holding a default route")
+}
+
+// parseBondOpts validates -o bond_slaves and -o bond_mode. The bond is the
+// parent interface, or the parent of its 802.1Q sub-interface.
+func parseBondOpts(n *network, slavesOpt, modeOpt string) error {
+	if slavesOpt == "" {
+		if modeOpt != "" {
+			return fmt.Errorf("bond_mode requires bond_slaves")
+		}
+		return nil
+	}
+	for _, slave := range strings.Split(slavesOpt, ",") {
+		if slave = strings.TrimSpace(slave); slave != "" {
+			n.bondSlaves = append(n.bondSlaves, slave)
+		}
+	}
+	if modeOpt == "" {
+		modeOpt = "balance-rr"
+	}
+	if _, ok := bondModes[modeOpt]; !ok {
+		return fmt.Errorf("unknown bond_mode [ %s ]", modeOpt)
+	}
+	n.bondMode = modeOpt
+	return nil
+}
+
+// bondIface returns the name of the bond a network builds its parent on
+func (n *network) bondIface() string {
+	if parent, _, ok := parseVlanIface(n.ifaceOpt); ok {
+		return parent
+	}
+	return n.ifaceOpt
+}
+
+// ensureParentLinks creates the bond and 802.1Q sub-interface a network
+// parent is built from when they are missing. The links are held for the
+// network until it is added to the network table or released, so a
+// concurrent failed create on the same parent never deletes them.
+func (driver *driver) ensureParentLinks(n *network) error {
+	driver.holdParentLinks(n)
+	if len(n.bondSlaves) > 0 {
+		if err := driver.ensureBondIface(n.bondIface(), n.id, n.bondSlaves, n.bondMode); err != nil {
+			driver.unholdParentLinks(n.id)
+			return err
+		}
+	}
+	if _, _, ok := parseVlanIface(n.ifaceOpt); ok {
+		if err := driver.ensureVlanIface(n.ifaceOpt, n.id); err != nil {
+			driver.unholdParentLinks(n.id)
+			driver.releaseOwnedLink(n.bondIface(), n.id)
+			return err
+		}
+	}
+	return nil
+}
+
+// holdParentLinks records the parent links a network uses before it is in
+// the network table
+func (driver *driver) holdParentLinks(n *network) {
+	driver.Lock()
+	defer driver.Unlock()
+
+	if driver.linkHolds == nil {
+		driver.linkHolds = map[string]map[string]bool{}
+	}
+	for _, name := range []string{n.ifaceOpt, n.bondIface()} {
+		if driver.linkHolds[name] == nil {
+			driver.linkHolds[name] = map[string]bool{}
+		}
+		driver.linkHolds[name][n.id] = true
+	}
+}
+
+// unholdParentLinks drops the holds of a network on its parent links
+func (driver *driver) unholdParentLinks(nid string) {
+	driver.Lock()
+	defer driver.Unlock()
+
+	driver.dropLinkHolds(nid)
+}
+
+// dropLinkHolds drops the holds of a network, the caller holds the driver lock
+func (driver *driver) dropLinkHolds(nid string) {
+	for name, holders := range driver.linkHolds {
+		delete(holders, nid)
+		if len(holders) == 0 {
+			delete(driver.linkHolds, name)
+		}
+	}
+}
+
+// releaseParentLinks deletes the sub-interface and bond the plugin created
+// for a network once no other network uses them
+func (driver *driver) releaseParentLinks(n *network) {
+	driver.unholdParentLinks(n.id)
+	driver.releaseOwnedLink(n.ifaceOpt, n.id)
+	if parent, _, ok := parseVlanIface(n.ifaceOpt); ok {
+		driver.releaseOwnedLink(parent, n.id)
+	}
+}
+
+// ensureBondIface creates a bond of the slaves and records it as owned by
+// the plugin. An existing bond is used as is.
+func (driver *driver) ensureBondIface(name, nid string, slaves []string, mode string) error {
+	if link, err := netlink.LinkByName(name); err == nil {
+		if link.Type() != "bond" {
+			return fmt.Errorf("parent interface [ %s ] exists and is not a bond", name)
+		}
+		return netlink.LinkSetUp(link)
+	}
+	bond := netlink.NewLinkBond(netlink.LinkAttrs{Name: name})
+	bond.Mode = netlink.BondMode(bondModes[mode])
+	bond.Miimon = bondMiimon
+	if err := netlink.LinkAdd(bond); err != nil {
+		if err == syscall.EEXIST {
+			return nil
+		}
+		return fmt.Errorf("unable to create the bond [ %s ]: %s", name, err)
+	}
+	log.Infof("Created the bond [ %s ] in mode [ %s ] with the slaves [ %s ]", name, mode, strings.Join(slaves, ","))
+	driver.addOwnedLink(name)
+	if err := enslave(name, slaves); err != nil {
+		driver.releaseOwnedLink(name, nid)
+		return err
+	}
+	return nil
+}
+
+// slaveState is the state of a link before it was enslaved to a bond
+type slaveState struct {
+	link   netlink.Link
+	up     bool
+	master int
+}
+
+// enslave adds the slaves to a bond and brings them all up. On failure the
+// slaves already touched get their original master and up state back, a
+// host uplink is never left down.
+func enslave(name string, slaves []string) (err error) {
+	bond, err := netlink.LinkByName(name)
+	if err != nil {
+		return err
+	}
+	var saved []*slaveState
+	for _, s := range slaves {
+		slave, err := netlink.LinkByName(s)
+		if err != nil {
+			return fmt.Errorf("bond slave [ %s ] was not found on the host", s)
+		}
+		saved = append(saved, &slaveState{
+			link:   slave,
+			up:     slave.Attrs().Flags&net.FlagUp != 0,
+			master: slave.Attrs().MasterIndex,
+		})
+	}
+	var touched []*slaveState
+	defer func() {
+		if err != nil {
+			restoreSlaves(touched)
+		}
+	}()
+	for _, st := range saved {
+		s := st.link.Attrs().Name
+		touched = append(touched, st)
+		// a slave must be down to be enslaved
+		if err := netlink.LinkSetDown(st.link); err != nil {
+			return fmt.Errorf("unable to disable the bond slave [ %s ]: %s", s, err)
+		}
+		if err := netlink.LinkSetMasterByIndex(st.link, bond.Attrs().Index); err != nil {
+			return fmt.Errorf("unable to add the slave [ %s ] to the bond [ %s ]: %s", s, name, err)
+		}
+		if err := netlink.LinkSetUp(st.link); err != nil {
+			return fmt.Errorf("unable to enable the bond slave [ %s ]: %s", s, err)
+		}
+	}
+	return netlink.LinkSetUp(bond)
+}
+
+// restoreSlaves puts links back on their original master and in their
+// original up state, errors are only logged
+func restoreSlaves(slaves []*slaveState) {
+	for _, st := range slaves {
+		name := st.link.Attrs().Name
+		var err error
+		if st.master > 0 {
+			err = netlink.LinkSetMasterByIndex(st.link, st.master)
+		} else {
+			err = netlink.LinkSetNoMaster(st.link)
+		}
+		if err != nil {
+			log.Errorf("Unable to restore the master of the bond slave [ %s ]: %s", name, err)
+		}
+		if st.up {
+			err = netlink.LinkSetUp(st.link)
+		} else {
+			err = netlink.LinkSetDown(st.link)
+		}
+		if err != nil {
+			log.Errorf("Unable to restore the state of the bond slave [ %s ]: %s", name, err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package ipvlan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBondOpts(t *testing.T) {
+	tests := []struct {
+		name      string
+		slavesOpt string
+		modeOpt   string
+		slaves    []string
+		mode      string
+		wantErr   bool
+	}{
+		{name: "no bond"},
+		{name: "default mode", slavesOpt: "eth1,eth2", slaves: []string{"eth1", "eth2"}, mode: "balance-rr"},
+		{name: "mode", slavesOpt: "eth1,eth2", modeOpt: "802.3ad", slaves: []string{"eth1", "eth2"}, mode: "802.3ad"},
+		{name: "spaces and empty entries", slavesOpt: " eth1, ,eth2 ,", slaves: []string{"eth1", "eth2"}, mode: "balance-rr"},
+		{name: "single slave", slavesOpt: "eth1", modeOpt: "active-backup", slaves: []string{"eth1"}, mode: "active-backup"},
+		{name: "unknown mode", slavesOpt: "eth1,eth2", modeOpt: "round-robin", wantErr: true},
+		{name: "mode without slaves", modeOpt: "802.3ad", wantErr: true},
+	}
+	for _, tt := range tests {
+		n := &network{id: "n"}
+		err := parseBondOpts(n, tt.slavesOpt, tt.modeOpt)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseBondOpts() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(n.bondSlaves, tt.slaves) || n.bondMode != tt.mode {
+			t.Errorf("%s: parseBondOpts() = %v %q, want %v %q", tt.name, n.bondSlaves, n.bondMode, tt.slaves, tt.mode)
+		}
+	}
+}
+
+func TestBondIface(t *testing.T) {
+	tests := []struct {
+		parent string
+		want   string
+	}{
+		{"bond0", "bond0"},
+		{"bond0.20", "bond0"},
+		{"eth1", "eth1"},
+	}
+	for _, tt := range tests {
+		if got := (&network{ifaceOpt: tt.parent}).bondIface(); got != tt.want {
+			t.Errorf("bondIface(%s) = %s, want %s", tt.parent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package ipvlan
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/libnetwork/types"
+	"github.com/vishvananda/netlink"
+)
+
+type programExternal struct {
+	NetworkID  string
+	EndpointID string
+	Options    struct {
+		// the -p port bindings of the endpoint
+		PortMap []types.PortBinding `json:"com.docker.network.portmap"`
+	}
+}
+
+type revokeExternal struct {
+	NetworkID  string
+	EndpointID string
+}
+
+// programExternalConnectivity publishes the -p ports of an endpoint of an
+// l3s network on the parent address. L2 containers sit on the parent subnet
+// and are reached directly.
+func (driver *driver) programExternalConnectivity(w http.ResponseWriter, r *http.Request) {
+	var p programExternal
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Program external connectivity request: %+v", &p)
+	n, err := driver.getNetwork(p.NetworkID)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	ep := n.endpointCopy(p.EndpointID)
+	if ep == nil {
+		errorResponsef(w, "endpoint [ %s ] not found in network [ %s ]", p.EndpointID, p.NetworkID)
+		return
+	}
+	// a repeated call replaces the bindings of the endpoint
+	if err := revokePortMap(ep); err != nil {
+		errorResponsef(w, "unable to remove the port bindings of endpoint [ %s ]: %s", ep.id, err)
+		return
+	}
+	n.setEndpointPortMap(ep.id, nil)
+	if len(p.Options.PortMap) == 0 {
+		emptyResponse(w)
+		return
+	}
+	bindings, err := resolvePortMap(n, ep, p.Options.PortMap)
+	if err != nil {
+		errorResponsef(w, "%s", err)
+		return
+	}
+	ep.portMap = bindings
+	if err := programPortMap(ep); err != nil {
+		revokePortMap(ep)
+		errorResponsef(w, "unable to publish the ports of endpoint [ %s ]: %s", ep.id, err)
+		return
+	}
+	n.setEndpointPortMap(ep.id, bindings)
+	if err := driver.saveState(); err != nil {
+		revokePortMap(ep)
+		n.setEndpointPortMap(ep.id, nil)
+		errorResponsef(w, "unable to persist the port bindings of endpoint [ %s ]: %s", ep.id, err)
+		return
+	}
+	emptyResponse(w)
+	log.Debugf("Programmed external connectivity of endpoint %s", ep.id)
+}
+
+// revokeExternalConnectivity removes the port bindings of an endpoint
+func (driver *driver) revokeExternalConnectivity(w http.ResponseWriter, r *http.Request) {
+	var rv revokeExternal
+	if err := json.NewDecoder(r.Body).Decode(&rv); err != nil {
+		errorResponsef(w, "unable to decode JSON payload: %s", err)
+		return
+	}
+	log.Debugf("Revoke external connectivity request: %+v", &rv)
+	if n, err := driver.getNetwork(rv.NetworkID); err == nil {
+		if ep := n.endpointCopy(rv.EndpointID); ep != nil && len(ep.portMap) > 0 {
+			if err := revokePortMap(ep); err != nil {
+				errorResponsef(w, "unable to remove the port bindings of endpoint [ %s ]: %s", ep.id, err)
+				return
+			}
+			n.setEndpointPortMap(ep.id, nil)
+			if err := driver.saveState(); err != nil {
+				errorResponsef(w, "unable to persist the revoke of endpoint [ %s ]: %s", ep.id, err)
+				return
+			}
+		}
+	}
+	emptyResponse(w)
+	log.Debugf("Revoked external connectivity of endpoint %s", rv.EndpointID)
+}
+
+// resolvePortMap validates the port bindings of an endpoint and fills in the
+// host address and port. Bindings without a host address use the first IPv4
+// address of the parent, bindings without a host port keep the container port.
+// Only l3s slaves pass the host netfilter hooks, on the other modes conntrack
+// never sees the replies and the DNAT is never reversed.
+func resolvePortMap(n *network, ep *endpoint, portMap []types.PortBinding) ([]types.PortBinding, error) {
+	if n.macvlan() || n.mode() != ipVlanL3S {
+		return nil, fmt.Errorf("publishing ports requires mode [ %s ], the replies of the %s network [ %s ] "+
+			"bypass the host netfilter hooks", ipVlanL3S, n.mode(), n.id)
+	}
+	if ep.addr == nil {
+		return nil, fmt.Errorf("publishing ports requires an IPv4 address on endpoint [ %s ]", ep.id)
+	}
+	var parentIP net.IP
+	bindings := make([]types.PortBinding, 0, len(portMap))
+	for _, b := range portMap {
+		if b.Proto != types.TCP && b.Proto != types.UDP {
+			return nil, fmt.Errorf("unable to publish the port [ %d ], unsupported protocol [ %s ]", b.Port, b.Proto)
+		}
+		if b.HostIP == nil || b.HostIP.IsUnspecified() {
+			if parentIP == nil {
+				ip, err := parentAddr(n)
+				if err != nil {
+					return nil, err
+				}
+				parentIP = ip
+			}
+			b.HostIP = parentIP
+		}
+		if b.HostIP.To4() == nil {
+			return nil, fmt.Errorf("unable to publish the port [ %d ] on [ %s ], only IPv4 is supported", b.Port, b.HostIP)
+		}
+		if b.HostPort == 0 {
+			log.Infof("No host port for the port [ %d/%s ] of endpoint [ %s ], publishing it as is", b.Port, b.Proto, ep.id)
+			b.HostPort = b.Port
+		}
+		// a binding has a single container port, a host range would send
+		// every host port of it to that one port
+		if b.HostPortEnd > b.HostPort {
+			return nil, fmt.Errorf("unable to publish the host ports [ %d-%d ] to the single container port [ %d ], "+
+				"publish one host port per container port", b.HostPort, b.HostPortEnd, b.Port)
+		}
+		b.HostPortEnd = b.HostPort
+		b.IP = ep.addr.IP
+		bindings = append(bindings, b)
+	}
+	return bindings, nil
+}
+
+// parentAddr returns the first IPv4 address of the network parent
+func parentAddr(n *network) (net.IP, error) {
+	parent, err := netlink.LinkByName(n.ifaceOpt)
+	if err != nil {
+		return nil, fmt.Errorf("parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+	}
+	addrs, err := netlink.AddrList(parent, netlink.FAMILY_V4)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("parent interface [ %s ] has no IPv4 address to publish ports on", n.ifaceOpt)
+	}
+	return addrs[0].IP, nil
+}
+
+// portMapRules returns the DNAT rules of a binding for traffic arriving on
+// the host and originating from it, the hairpin masquerade letting a
+// container reach its own published port and the forward accept that a
+// DROP policy such as the docker one would otherwise need
+func portMapRules(b types.PortBinding) [][]string {
+	proto := b.Proto.String()
+	dport := strconv.Itoa(int(b.HostPort))
+	port := strconv.Itoa(int(b.Port))
+	dest := net.JoinHostPort(b.IP.String(), port)
+	return [][]string{
+		{"PREROUTING", "-t", "nat", "-d", b.HostIP.String(), "-p", proto, "--dport", dport, "-j", "DNAT", "--to-destination", dest},
+		{"OUTPUT", "-t", "nat", "-d", b.HostIP.String(), "-p", proto, "--dport", dport, "-j", "DNAT", "--to-destination", dest},
+		{"POSTROUTING", "-t", "nat", "-s", b.IP.String(), "-d", b.IP.String(), "-p", proto, "--dport", port, "-j", "MASQUERADE"},
+		{"FORWARD", "-d", b.IP.String(), "-p", proto, "--dport", port, "-j", "ACCEPT"},
+	}
+}
+
+// programPortMap installs the rules of every binding of an endpoint
+func programPortMap(ep *endpoint) error {
+	for _, b := range ep.portMap {
+		for _, rule := range portMapRules(b) {
+			if err := insertRule(rule); err != nil {
+				return err
+			}
+		}
+		log.Infof("Published [ %s:%d/%s ] to endpoint [ %s ] port [ %d ]", b.HostIP, b.HostPort, b.Proto, ep.id, b.Port)
+	}
+	return nil
+}
+
+// revokePortMap removes the rules of every binding of an endpoint, rules
+// that are already gone are skipped
+func revokePortMap(ep *endpoint) error {
+	for _, b := range ep.portMap {
+		for _, rule := range portMapRules(b) {
+			if err := deleteRule(rule); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
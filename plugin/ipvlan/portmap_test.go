@@ -0,0 +1,105 @@
+package ipvlan
+
+import (
+	"net"
+	"testing"
+
+	"github.com/docker/libnetwork/types"
+)
+
+func TestResolvePortMap(t *testing.T) {
+	addr := &net.IPNet{IP: net.ParseIP("10.1.0.5").To4(), Mask: net.CIDRMask(24, 32)}
+	hostIP := net.ParseIP("192.0.2.1").To4()
+	tests := []struct {
+		name    string
+		n       *network
+		ep      *endpoint
+		in      types.PortBinding
+		want    types.PortBinding
+		wantErr bool
+	}{
+		{
+			name: "l3s",
+			n:    &network{id: "n", modeOpt: ipVlanL3S},
+			ep:   &endpoint{id: "e", addr: addr},
+			in:   types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			want: types.PortBinding{Proto: types.TCP, Port: 80, IP: addr.IP, HostIP: hostIP, HostPort: 8080, HostPortEnd: 8080},
+		},
+		{
+			name: "single port range",
+			n:    &network{id: "n", modeOpt: ipVlanL3S},
+			ep:   &endpoint{id: "e", addr: addr},
+			in:   types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080, HostPortEnd: 8080},
+			want: types.PortBinding{Proto: types.TCP, Port: 80, IP: addr.IP, HostIP: hostIP, HostPort: 8080, HostPortEnd: 8080},
+		},
+		{
+			name:    "host port range",
+			n:       &network{id: "n", modeOpt: ipVlanL3S},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8000, HostPortEnd: 8010},
+			wantErr: true,
+		},
+		{
+			name:    "l3",
+			n:       &network{id: "n", modeOpt: ipVlanL3},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			wantErr: true,
+		},
+		{
+			name:    "l3routing",
+			n:       &network{id: "n", modeOpt: ipVlanL3Routing},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			wantErr: true,
+		},
+		{
+			name: "l3s nat without host port",
+			n:    &network{id: "n", modeOpt: ipVlanL3S, nat: true},
+			ep:   &endpoint{id: "e", addr: addr},
+			in:   types.PortBinding{Proto: types.UDP, Port: 53, HostIP: hostIP},
+			want: types.PortBinding{Proto: types.UDP, Port: 53, IP: addr.IP, HostIP: hostIP, HostPort: 53, HostPortEnd: 53},
+		},
+		{
+			name:    "l2 with a host shim",
+			n:       &network{id: "n", modeOpt: ipVlanL2, hostShim: true},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			wantErr: true,
+		},
+		{
+			name:    "macvlan",
+			n:       &network{id: "n", kind: driverKindMacvlan},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 only endpoint",
+			n:       &network{id: "n", modeOpt: ipVlanL3S},
+			ep:      &endpoint{id: "e"},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: hostIP, HostPort: 8080},
+			wantErr: true,
+		},
+		{
+			name:    "IPv6 host address",
+			n:       &network{id: "n", modeOpt: ipVlanL3S},
+			ep:      &endpoint{id: "e", addr: addr},
+			in:      types.PortBinding{Proto: types.TCP, Port: 80, HostIP: net.ParseIP("2001:db8::1"), HostPort: 8080},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := resolvePortMap(tt.n, tt.ep, []types.PortBinding{tt.in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if len(got) != 1 || !got[0].Equal(&tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
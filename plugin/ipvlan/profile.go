@@ -0,0 +1,76 @@
+package ipvlan
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// libnetwork label for the MTU of a network
+	driverMTUOpt = "com.docker.network.driver.mtu"
+	// IPv6 links must carry at least 1280 bytes per rfc2460
+	minMTUV6 = 1280
+)
+
+// resolveLinkProfile sets the MTU and tx queue length of the network's ipvlan
+// links. Network options win over the plugin flags, with neither set the MTU
+// is inherited from the parent so jumbo frame parents are detected. An ipvlan
+// child can never exceed the MTU of its parent.
+func (driver *driver) resolveLinkProfile(n *network, mtuOpt, txQLenOpt string) error {
+	mtu := driver.pluginConfig.mtu
+	if mtuOpt != "" {
+		v, err := strconv.Atoi(mtuOpt)
+		if err != nil {
+			return fmt.Errorf("invalid mtu [ %s ]: %s", mtuOpt, err)
+		}
+		mtu = v
+	}
+	parentMTU := 0
+	if parent, err := netlink.LinkByName(n.ifaceOpt); err == nil {
+		parentMTU = parent.Attrs().MTU
+	} else {
+		log.Warnf("Parent interface [ %s ] not found, the MTU will be validated at join", n.ifaceOpt)
+	}
+	if mtu <= 0 {
+		mtu = parentMTU
+		if parentMTU > defaultMTU {
+			log.Infof("Detected jumbo frames on the parent interface [ %s ], using an MTU of [ %d ]", n.ifaceOpt, parentMTU)
+		}
+	}
+	if mtu > 0 {
+		lower := minMTU
+		if n.cidrV6 != nil {
+			lower = minMTUV6
+		}
+		if mtu < lower {
+			return fmt.Errorf("mtu [ %d ] must be at least [ %d ] bytes", mtu, lower)
+		}
+		if parentMTU > 0 && mtu > parentMTU {
+			return fmt.Errorf("mtu [ %d ] exceeds the MTU [ %d ] of the parent interface [ %s ]", mtu, parentMTU, n.ifaceOpt)
+		}
+	}
+	n.mtu = mtu
+
+	n.txQueueLen = driver.pluginConfig.txQueueLen
+	if txQLenOpt != "" {
+		v, err := strconv.Atoi(txQLenOpt)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid txqueuelen [ %s ], must be a positive number", txQLenOpt)
+		}
+		n.txQueueLen = v
+	}
+	log.Debugf("Link profile for network [ %s ]: mtu [ %d ] txqueuelen [ %d ]", n.id, n.mtu, n.txQueueLen)
+	return nil
+}
+
+// linkMTU returns the MTU to set on a new ipvlan link of the network,
+// networks created while their parent was missing inherit it at join
+func (n *network) linkMTU(parent netlink.Link) int {
+	if n.mtu > 0 {
+		return n.mtu
+	}
+	return parent.Attrs().MTU
+}
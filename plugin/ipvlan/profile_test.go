@@ -0,0 +1,69 @@
+package ipvlan
+
+import (
+	"net"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+)
+
+func TestResolveLinkProfile(t *testing.T) {
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		t.Skipf("loopback interface not found: %s", err)
+	}
+	loMTU := lo.Attrs().MTU
+	_, v6, _ := net.ParseCIDR("2001:db8::/64")
+	tests := []struct {
+		name      string
+		parent    string
+		cidrV6    *net.IPNet
+		pluginMTU int
+		mtuOpt    string
+		txQLenOpt string
+		want      int
+		wantTxQ   int
+		wantErr   bool
+	}{
+		{name: "inherit the parent", parent: "lo", want: loMTU},
+		{name: "plugin flag", parent: "lo", pluginMTU: 9000, want: 9000},
+		{name: "option over the plugin flag", parent: "lo", pluginMTU: 9000, mtuOpt: "1400", want: 1400},
+		{name: "missing parent", parent: testParent, want: 0},
+		{name: "missing parent with an option", parent: testParent, mtuOpt: "9000", want: 9000},
+		{name: "minimum", parent: "lo", mtuOpt: "68", want: 68},
+		{name: "below the minimum", parent: "lo", mtuOpt: "67", wantErr: true},
+		{name: "ipv6 minimum", parent: "lo", cidrV6: v6, mtuOpt: "1280", want: 1280},
+		{name: "below the ipv6 minimum", parent: "lo", cidrV6: v6, mtuOpt: "1279", wantErr: true},
+		{name: "plugin flag below the minimum", parent: testParent, pluginMTU: 60, wantErr: true},
+		{name: "above the parent", parent: "lo", mtuOpt: "70000", wantErr: true},
+		{name: "not a number", parent: "lo", mtuOpt: "jumbo", wantErr: true},
+		{name: "txqueuelen", parent: "lo", txQLenOpt: "2000", want: loMTU, wantTxQ: 2000},
+		{name: "negative txqueuelen", parent: "lo", txQLenOpt: "-1", wantErr: true},
+		{name: "invalid txqueuelen", parent: "lo", txQLenOpt: "long", wantErr: true},
+	}
+	for _, tt := range tests {
+		d := &driver{pluginConfig: pluginConfig{mtu: tt.pluginMTU}}
+		n := &network{id: "n", ifaceOpt: tt.parent, cidrV6: tt.cidrV6}
+		err := d.resolveLinkProfile(n, tt.mtuOpt, tt.txQLenOpt)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: resolveLinkProfile() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if n.mtu != tt.want || n.txQueueLen != tt.wantTxQ {
+			t.Errorf("%s: mtu %d txqueuelen %d, want %d %d", tt.name, n.mtu, n.txQueueLen, tt.want, tt.wantTxQ)
+		}
+	}
+}
+
+func TestLinkMTU(t *testing.T) {
+	parent := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: testParent, MTU: 9000}}
+	if got := (&network{mtu: 1400}).linkMTU(parent); got != 1400 {
+		t.Errorf("linkMTU() = %d, want the network MTU 1400", got)
+	}
+	if got := (&network{}).linkMTU(parent); got != 9000 {
+		t.Errorf("linkMTU() = %d, want the parent MTU 9000", got)
+	}
+}
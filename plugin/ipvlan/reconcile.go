@@ -0,0 +1,328 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/samalba/dockerclient"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	reconcileRetries  = 10
+	reconcileInterval = 3 * time.Second
+	// backoff of the prefixes waiting for the routing manager
+	advertRetryInterval    = time.Second
+	advertRetryMaxInterval = 30 * time.Second
+)
+
+// startReconcile retries the reconciliation until the Docker daemon answers
+// since the plugin is frequently started before or alongside the daemon.
+func (driver *driver) startReconcile() {
+	for i := 1; i <= reconcileRetries; i++ {
+		err := driver.reconcile()
+		if err == nil {
+			return
+		}
+		log.Warnf("Reconciliation attempt [ %d/%d ] against the Docker daemon failed: %s", i, reconcileRetries, err)
+		time.Sleep(reconcileInterval)
+	}
+	log.Errorf("Giving up reconciling the plugin state with the Docker daemon, networks unknown to the plugin will fail to join")
+}
+
+// reconcile rebuilds the network table from the networks the Docker daemon
+// has bound to this driver, then re-installs the host routes and BGP
+// advertisements that a daemon or host restart may have flushed.
+func (driver *driver) reconcile() error {
+	resources, err := driver.client.ListNetworks("")
+	if err != nil {
+		return fmt.Errorf("unable to list the Docker networks: %s", err)
+	}
+	known := map[string]bool{}
+	for _, nr := range resources {
+		kind := driverKindIpvlan
+		switch {
+		case nr.Driver == driver.driverName:
+		case driver.macvlanName != "" && nr.Driver == driver.macvlanName:
+			kind = driverKindMacvlan
+		default:
+			continue
+		}
+		known[nr.ID] = true
+		n, err := driver.networkFromResource(nr, kind)
+		if err != nil {
+			log.Errorf("Unable to reconcile network [ %s ]: %s", nr.Name, err)
+			continue
+		}
+		if existing, err := driver.getNetwork(nr.ID); err == nil {
+			// the persisted record wins, only learn endpoints it is missing
+			for id, ep := range n.endpoints {
+				if existing.endpoint(id) == nil {
+					existing.addEndpoint(ep)
+				}
+			}
+			n = existing
+		} else {
+			log.Infof("Recovered network [ %s ] with the subnet [ %s ] from the Docker daemon", nr.Name, n.cidr)
+			driver.addNetwork(n)
+		}
+		// a host reboot flushes the bonds and sub-interfaces the plugin created
+		if err := driver.ensureParentLinks(n); err != nil {
+			log.Errorf("Unable to restore the parent interface of network [ %s ]: %s", nr.Name, err)
+		}
+		// the network is already in the table, it keeps the links
+		driver.unholdParentLinks(n.id)
+		if err := driver.restoreNetworkRoutes(n); err != nil {
+			log.Errorf("Unable to restore the routes for network [ %s ]: %s", nr.Name, err)
+		}
+		if err := natOut(n); err != nil {
+			log.Errorf("Unable to restore the outbound NAT rule for network [ %s ]: %s", nr.Name, err)
+		}
+		if err := ensureHostShim(n); err != nil {
+			log.Errorf("Unable to restore the host shim for network [ %s ]: %s", nr.Name, err)
+		}
+	}
+	// networks removed while the plugin was not running. Only the networks
+	// restored from the state file are pruned, libnetwork stores a network
+	// after the driver created it so a new one may not be listed yet.
+	for _, n := range driver.getNetworks() {
+		if driver.restored[n.id] && !known[n.id] {
+			log.Infof("Removing network [ %s ] no longer known to the Docker daemon", n.id)
+			if err := driver.teardownNetwork(n); err != nil {
+				log.Errorf("Unable to tear down network [ %s ]: %s", n.id, err)
+			}
+			driver.delNetwork(n.id)
+		}
+	}
+	driver.checkParents()
+	return driver.saveState()
+}
+
+// restoreNetworkRoutes re-installs the link routes of a network and
+// re-advertises its prefix. The routing manager starts asynchronously, a
+// prefix it does not take yet is queued and retried in the background.
+func (driver *driver) restoreNetworkRoutes(n *network) error {
+	if _, err := addNetworkRoutes(n); err != nil {
+		return err
+	}
+	if err := advertiseNetwork(n); err != nil {
+		log.Warnf("Queueing the advertisement of network [ %s ]: %s", n.id, err)
+		driver.queueAdvertisement(n.id)
+	}
+	return nil
+}
+
+// queueAdvertisement queues the prefix of a network for the retry loop and
+// starts the loop when it is not running
+func (driver *driver) queueAdvertisement(nid string) {
+	driver.Lock()
+	defer driver.Unlock()
+
+	if driver.pendingAdverts == nil {
+		driver.pendingAdverts = map[string]bool{}
+	}
+	driver.pendingAdverts[nid] = true
+	if !driver.advertRetrying {
+		driver.advertRetrying = true
+		go driver.retryAdvertisements()
+	}
+}
+
+// retryAdvertisements advertises the queued prefixes with a backoff until
+// the queue is empty
+func (driver *driver) retryAdvertisements() {
+	interval := advertRetryInterval
+	for {
+		time.Sleep(interval)
+		if driver.advertisePending() == 0 {
+			return
+		}
+		if interval *= 2; interval > advertRetryMaxInterval {
+			interval = advertRetryMaxInterval
+		}
+	}
+}
+
+// advertisePending advertises the queued prefixes once and returns how many
+// are still queued. Networks deleted or degraded since are dropped, the
+// parent recovery advertises a degraded one again.
+func (driver *driver) advertisePending() int {
+	driver.Lock()
+	var ids []string
+	for nid := range driver.pendingAdverts {
+		ids = append(ids, nid)
+	}
+	driver.Unlock()
+
+	for _, nid := range ids {
+		if !driver.advertiseQueued(nid) {
+			continue
+		}
+		driver.Lock()
+		delete(driver.pendingAdverts, nid)
+		driver.Unlock()
+	}
+
+	driver.Lock()
+	defer driver.Unlock()
+	if len(driver.pendingAdverts) == 0 {
+		driver.advertRetrying = false
+	}
+	return len(driver.pendingAdverts)
+}
+
+// advertiseQueued advertises the prefix of a queued network and reports
+// whether it leaves the queue. The network lock keeps a teardown from
+// withdrawing the prefix while it is advertised.
+func (driver *driver) advertiseQueued(nid string) bool {
+	n, err := driver.getNetwork(nid)
+	if err != nil {
+		return true
+	}
+	n.hostLock.Lock()
+	defer n.hostLock.Unlock()
+	if n.deleting || n.degradedReason() != "" {
+		return true
+	}
+	if err := advertiseNetwork(n); err != nil {
+		log.Debugf("Advertisement of network [ %s ] still pending: %s", nid, err)
+		return false
+	}
+	log.Infof("Advertised the queued prefix [ %s ] of network [ %s ]", n.cidr, nid)
+	return true
+}
+
+// networkFromResource builds a driver network from a Docker network resource
+func (driver *driver) networkFromResource(nr *dockerclient.NetworkResource, kind string) (*network, error) {
+	// options of older plugins that are no longer supported do not block
+	// the recovery of the network
+	generic := map[string]interface{}{}
+	for key, val := range nr.Options {
+		if _, ok := lookupOption(key, kind); !ok && !strings.Contains(key, ".") {
+			log.Warnf("Ignoring the unsupported option [ %s ] of network [ %s ]", key, nr.Name)
+			continue
+		}
+		generic[key] = val
+	}
+	opts, err := parseOptions(scopeNetwork, kind, map[string]interface{}{genericOpt: generic})
+	if err != nil {
+		return nil, err
+	}
+	n := &network{
+		id:        nr.ID,
+		kind:      kind,
+		endpoints: endpointTable{},
+		modeOpt:   opts["mode"],
+	}
+	n.pinMode(driver.pluginConfig.mode)
+	hostIface, parent := opts["host_iface"], opts["parent"]
+	if hostIface == "" && parent == "" {
+		hostIface = ipVlanEthIface
+	}
+	var gateway string
+	for _, cfg := range nr.IPAM.Config {
+		if gateway == "" {
+			gateway = strings.Split(cfg.Gateway, "/")[0]
+		}
+	}
+	hostIface, err = resolveAutoIface(hostIface, gateway)
+	if err != nil {
+		return nil, err
+	}
+	if parent, err = resolveAutoIface(parent, gateway); err != nil {
+		return nil, err
+	}
+	iface, err := resolveParentIface(hostIface, parent, opts["vlan_id"])
+	if err != nil {
+		return nil, err
+	}
+	n.ifaceOpt = iface
+	if n.ifaceOpt == "" {
+		n.ifaceOpt = ipVlanEthIface
+	}
+	if err := parseBondOpts(n, opts["bond_slaves"], opts["bond_mode"]); err != nil {
+		return nil, err
+	}
+	if n.macvlan() {
+		if _, err := parseMacvlanMode(n.modeOpt); err != nil {
+			return nil, err
+		}
+	} else if _, err := setIpVlanMode(n.modeOpt); err != nil {
+		return nil, err
+	}
+	if err := parseIpvlanFlag(n, opts["ipvlan_flag"]); err != nil {
+		return nil, err
+	}
+	if n.limits, err = parseRateLimit(rateLimit{}, opts.get); err != nil {
+		return nil, err
+	}
+	auxAddrs := map[string]net.IP{}
+	for _, cfg := range nr.IPAM.Config {
+		if cfg.Subnet == "" {
+			continue
+		}
+		for name, aux := range cfg.AuxAddress {
+			auxAddrs[name] = net.ParseIP(strings.Split(aux, "/")[0])
+		}
+		_, cidr, err := net.ParseCIDR(cfg.Subnet)
+		if err != nil {
+			return nil, err
+		}
+		// older daemons report the gateway in CIDR notation
+		gateway := strings.Split(cfg.Gateway, "/")[0]
+		if cidr.IP.To4() == nil {
+			n.cidrV6, n.gatewayV6 = cidr, gateway
+		} else {
+			n.cidr, n.gateway = cidr, gateway
+		}
+	}
+	mtuOpt := opts["mtu"]
+	if mtuOpt == "" {
+		mtuOpt = opts[driverMTUOpt]
+	}
+	if err := driver.resolveLinkProfile(n, mtuOpt, opts["txqueuelen"]); err != nil {
+		return nil, err
+	}
+	if err := parseDhcpOpt(n, opts[dhcpOpt]); err != nil {
+		return nil, err
+	}
+	if err := parseDadOpt(n, opts[dadOpt]); err != nil {
+		return nil, err
+	}
+	if err := parseAnnounceOpts(n, opts[announceCountOpt], opts[announceIntervalOpt]); err != nil {
+		return nil, err
+	}
+	if err := parseNatOpts(n, opts["nat"], opts["snat_ip"]); err != nil {
+		return nil, err
+	}
+	if err := parseHostShim(n, opts["host_shim"], auxAddrs, &driver.ipam); err != nil {
+		return nil, err
+	}
+	if n.routes, err = parseRoutes(n, opts[routesOpt]); err != nil {
+		return nil, err
+	}
+	for _, er := range nr.Containers {
+		if er.EndpointID == "" || (er.IPv4Address == "" && er.IPv6Address == "") {
+			continue
+		}
+		ep := &endpoint{id: er.EndpointID}
+		ep.mac, _ = net.ParseMAC(er.MacAddress)
+		if er.IPv4Address != "" {
+			if ep.addr, err = netlink.ParseIPNet(er.IPv4Address); err != nil {
+				log.Warnf("Ignoring endpoint [ %s ] with an invalid address [ %s ]", er.EndpointID, er.IPv4Address)
+				continue
+			}
+		}
+		if er.IPv6Address != "" {
+			if ep.addrV6, err = netlink.ParseIPNet(er.IPv6Address); err != nil {
+				log.Warnf("Ignoring endpoint [ %s ] with an invalid IPv6 address [ %s ]", er.EndpointID, er.IPv6Address)
+				continue
+			}
+		}
+		n.endpoints[er.EndpointID] = ep
+	}
+	return n, nil
+}
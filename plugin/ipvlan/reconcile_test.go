@@ -0,0 +1,243 @@
+package ipvlan
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/samalba/dockerclient"
+	"github.com/samalba/dockerclient/mockclient"
+)
+
+// the parent of the test networks does not exist, reconcile only logs the
+// links and routes it is unable to restore
+const testParent = "ipvtest0"
+
+func newTestDriver(t *testing.T, client dockerclient.Client) (*driver, func()) {
+	dir, err := ioutil.TempDir("", "ipvlan-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := newStateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &driver{
+		networks:   networkTable{},
+		ownedLinks: map[string]bool{},
+		dockerer:   dockerer{client: client},
+		driverName: "ipvlan",
+		store:      store,
+		restored:   map[string]bool{},
+		ipam:       ipam{pools: map[string]*pool{}},
+		pluginConfig: pluginConfig{
+			mode:      ipVlanL2,
+			hostIface: testParent,
+		},
+	}
+	return d, func() { os.RemoveAll(dir) }
+}
+
+func testResource(id, driverName string, opts map[string]string) *dockerclient.NetworkResource {
+	nr := &dockerclient.NetworkResource{
+		Name:    id,
+		ID:      id,
+		Driver:  driverName,
+		Options: map[string]string{"host_iface": testParent, "mode": ipVlanL2},
+		IPAM: dockerclient.IPAM{
+			Config: []dockerclient.IPAMConfig{{Subnet: "192.168.50.0/24", Gateway: "192.168.50.1"}},
+		},
+		Containers: map[string]dockerclient.EndpointResource{
+			"c1": {EndpointID: "ep1", MacAddress: "7a:42:c0:a8:32:02", IPv4Address: "192.168.50.2/24"},
+		},
+	}
+	for k, v := range opts {
+		nr.Options[k] = v
+	}
+	return nr
+}
+
+func TestReconcileRecoversNetworks(t *testing.T) {
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource{
+		testResource("net1", "ipvlan", map[string]string{"mtu": "1400", "txqueuelen": "500"}),
+		testResource("net2", "bridge", nil),
+	}, nil)
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+
+	if err := d.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	client.AssertExpectations(t)
+	if _, err := d.getNetwork("net2"); err == nil {
+		t.Errorf("network of another driver was recovered")
+	}
+	n, err := d.getNetwork("net1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.ifaceOpt != testParent || n.mode() != ipVlanL2 {
+		t.Errorf("got parent [ %s ] mode [ %s ]", n.ifaceOpt, n.mode())
+	}
+	if n.mtu != 1400 || n.txQueueLen != 500 {
+		t.Errorf("got mtu [ %d ] txqueuelen [ %d ], want 1400 and 500", n.mtu, n.txQueueLen)
+	}
+	if n.cidr.String() != "192.168.50.0/24" || n.gateway != "192.168.50.1" {
+		t.Errorf("got subnet [ %s ] gateway [ %s ]", n.cidr, n.gateway)
+	}
+	ep := n.endpoint("ep1")
+	if ep == nil || ep.addr.String() != "192.168.50.2/24" || ep.mac.String() != "7a:42:c0:a8:32:02" {
+		t.Errorf("endpoint not recovered: %+v", ep)
+	}
+	state, err := d.store.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Networks) != 1 || state.Networks[0].ID != "net1" {
+		t.Errorf("recovered network not persisted: %+v", state.Networks)
+	}
+}
+
+func TestReconcileDriverMTUOption(t *testing.T) {
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource{
+		testResource("net1", "ipvlan", map[string]string{driverMTUOpt: "1450"}),
+	}, nil)
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+
+	if err := d.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := d.getNetwork("net1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.mtu != 1450 {
+		t.Errorf("got mtu [ %d ], want 1450", n.mtu)
+	}
+}
+
+func TestReconcilePrunesRestoredNetworks(t *testing.T) {
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource{
+		testResource("kept", "ipvlan", nil),
+	}, nil)
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+
+	for _, id := range []string{"kept", "removed", "created"} {
+		d.addNetwork(&network{id: id, ifaceOpt: testParent, modeOpt: ipVlanL2, endpoints: endpointTable{}})
+	}
+	// created is served by CreateNetwork before libnetwork stores it
+	d.restored = map[string]bool{"kept": true, "removed": true}
+
+	if err := d.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]bool{"kept": true, "removed": false, "created": true} {
+		if _, err := d.getNetwork(id); (err == nil) != want {
+			t.Errorf("network [ %s ] present [ %t ], want [ %t ]", id, err == nil, want)
+		}
+	}
+}
+
+func TestReconcileKeepsPersistedRecord(t *testing.T) {
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource{
+		testResource("net1", "ipvlan", map[string]string{"mtu": "1400"}),
+	}, nil)
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+
+	persisted := &network{id: "net1", ifaceOpt: testParent, modeOpt: ipVlanL2, mtu: 9000, endpoints: endpointTable{}}
+	d.addNetwork(persisted)
+	d.restored["net1"] = true
+
+	if err := d.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := d.getNetwork("net1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != persisted || n.mtu != 9000 {
+		t.Errorf("the persisted network record was replaced")
+	}
+	if n.endpoint("ep1") == nil {
+		t.Errorf("the endpoint missing from the persisted record was not learned")
+	}
+}
+
+func TestReconcileListError(t *testing.T) {
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource(nil), errors.New("connection refused"))
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+
+	d.addNetwork(&network{id: "net1", ifaceOpt: testParent, modeOpt: ipVlanL2, endpoints: endpointTable{}})
+	d.restored["net1"] = true
+	if err := d.reconcile(); err == nil {
+		t.Fatal("expected an error when the daemon cannot be listed")
+	}
+	if _, err := d.getNetwork("net1"); err != nil {
+		t.Errorf("a network was pruned although the daemon did not answer")
+	}
+}
+
+func TestReconcilePinsMode(t *testing.T) {
+	nr := testResource("net1", "ipvlan", nil)
+	delete(nr.Options, "mode")
+	client := mockclient.NewMockClient()
+	client.On("ListNetworks", "").Return([]*dockerclient.NetworkResource{nr}, nil)
+	d, cleanup := newTestDriver(t, client)
+	defer cleanup()
+	d.pluginConfig.mode = ipVlanL3
+
+	if err := d.reconcile(); err != nil {
+		t.Fatal(err)
+	}
+	n, err := d.getNetwork("net1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.modeOpt != ipVlanL3 {
+		t.Errorf("got mode [ %s ], want the plugin mode [ %s ] pinned", n.modeOpt, ipVlanL3)
+	}
+	state, err := d.store.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Networks) != 1 || state.Networks[0].ModeOpt != ipVlanL3 {
+		t.Errorf("pinned mode not persisted: %+v", state.Networks)
+	}
+}
+
+func TestAdvertisePending(t *testing.T) {
+	d, cleanup := newTestDriver(t, mockclient.NewMockClient())
+	defer cleanup()
+
+	_, cidr, _ := net.ParseCIDR("10.9.0.0/24")
+	for _, id := range []string{"waiting", "degraded", "deleting"} {
+		d.addNetwork(&network{id: id, modeOpt: ipVlanL3Routing, cidr: cidr, endpoints: endpointTable{}})
+	}
+	d.networks["degraded"].degraded = "is down"
+	d.networks["deleting"].deleting = true
+	d.pendingAdverts = map[string]bool{"waiting": true, "degraded": true, "deleting": true, "deleted": true}
+	d.advertRetrying = true
+
+	// the routing manager is not running in the tests
+	if got := d.advertisePending(); got != 1 || !d.pendingAdverts["waiting"] {
+		t.Errorf("advertisePending() = %d %v, want only [ waiting ] queued", got, d.pendingAdverts)
+	}
+	if !d.advertRetrying {
+		t.Errorf("the retry loop stopped with a queued prefix")
+	}
+	d.delNetwork("waiting")
+	if got := d.advertisePending(); got != 0 || d.advertRetrying {
+		t.Errorf("advertisePending() = %d retrying %t after the delete, want 0 and stopped", got, d.advertRetrying)
+	}
+}
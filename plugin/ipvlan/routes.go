@@ -0,0 +1,108 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/libnetwork/types"
+)
+
+const (
+	routesOpt  = "routes"
+	genericOpt = "com.docker.network.generic"
+)
+
+// route is a static route installed in the container at join, routes
+// without a next hop are connected to the endpoint link
+type route struct {
+	dst     *net.IPNet
+	nextHop net.IP
+}
+
+func (r *route) String() string {
+	if r.nextHop == nil {
+		return r.dst.String()
+	}
+	return fmt.Sprintf("%s via %s", r.dst, r.nextHop)
+}
+
+func (r *route) staticRoute() *staticRoute {
+	if r.nextHop == nil {
+		return &staticRoute{Destination: r.dst.String(), RouteType: types.CONNECTED}
+	}
+	return &staticRoute{Destination: r.dst.String(), RouteType: types.NEXTHOP, NextHop: r.nextHop.String()}
+}
+
+// parseRoutes parses a comma separated list such as
+// "10.0.0.0/8 via 192.168.1.254,172.16.0.0/12" and validates it against the
+// network. Next hops must be on-link in one of the network subnets.
+func parseRoutes(n *network, spec string) ([]*route, error) {
+	var routes []*route
+	for _, item := range strings.Split(spec, ",") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 1 && (len(fields) != 3 || fields[1] != "via") {
+			return nil, fmt.Errorf("invalid route [ %s ], must be <cidr> [via <ip>]", strings.TrimSpace(item))
+		}
+		_, dst, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid route destination [ %s ]: %s", fields[0], err)
+		}
+		r := &route{dst: dst}
+		if len(fields) == 3 {
+			if r.nextHop = net.ParseIP(fields[2]); r.nextHop == nil {
+				return nil, fmt.Errorf("invalid next hop [ %s ] for route [ %s ]", fields[2], dst)
+			}
+			if (r.nextHop.To4() == nil) != (dst.IP.To4() == nil) {
+				return nil, fmt.Errorf("next hop [ %s ] and destination [ %s ] address families differ", r.nextHop, dst)
+			}
+			onLink := false
+			for _, subnet := range n.subnets() {
+				if subnet.Contains(r.nextHop) {
+					onLink = true
+				}
+			}
+			if !onLink {
+				return nil, fmt.Errorf("next hop [ %s ] of route [ %s ] is not in a subnet of the network", r.nextHop, dst)
+			}
+		}
+		if ones, _ := dst.Mask.Size(); ones == 0 && n.l2() {
+			return nil, fmt.Errorf("default route [ %s ] is set with --gateway in l2 mode", dst)
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// routeStrings returns the persisted form of routes
+func routeStrings(routes []*route) []string {
+	var s []string
+	for _, r := range routes {
+		s = append(s, r.String())
+	}
+	return s
+}
+
+// mergeRoutes appends the route sets to the static routes of a join
+// response, a later route to the same destination replaces an earlier one
+func mergeRoutes(routes []*staticRoute, sets ...[]*route) []*staticRoute {
+	index := map[string]int{}
+	for i, sr := range routes {
+		index[sr.Destination] = i
+	}
+	for _, set := range sets {
+		for _, r := range set {
+			sr := r.staticRoute()
+			if i, ok := index[sr.Destination]; ok {
+				routes[i] = sr
+				continue
+			}
+			index[sr.Destination] = len(routes)
+			routes = append(routes, sr)
+		}
+	}
+	return routes
+}
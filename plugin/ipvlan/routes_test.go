@@ -0,0 +1,106 @@
+package ipvlan
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/docker/libnetwork/types"
+)
+
+func testNetwork(mode, cidr, cidrV6 string) *network {
+	n := &network{id: "net1", modeOpt: mode}
+	if cidr != "" {
+		_, n.cidr, _ = net.ParseCIDR(cidr)
+	}
+	if cidrV6 != "" {
+		_, n.cidrV6, _ = net.ParseCIDR(cidrV6)
+	}
+	return n
+}
+
+func TestParseRoutes(t *testing.T) {
+	l2 := testNetwork(ipVlanL2, "192.168.1.0/24", "2001:db8::/64")
+	l3 := testNetwork(ipVlanL3, "192.168.1.0/24", "")
+	tests := []struct {
+		n       *network
+		spec    string
+		want    []string
+		wantErr bool
+	}{
+		{l2, "", nil, false},
+		{l2, "10.0.0.0/8", []string{"10.0.0.0/8"}, false},
+		{l2, "10.0.0.0/8 via 192.168.1.254, 172.16.0.0/12", []string{"10.0.0.0/8 via 192.168.1.254", "172.16.0.0/12"}, false},
+		{l2, "10.1.2.3/8", []string{"10.0.0.0/8"}, false},
+		{l2, "2001:db8:1::/48 via 2001:db8::1", []string{"2001:db8:1::/48 via 2001:db8::1"}, false},
+		{l2, " , 10.0.0.0/8,", []string{"10.0.0.0/8"}, false},
+		{l3, "0.0.0.0/0", []string{"0.0.0.0/0"}, false},
+		{l2, "0.0.0.0/0 via 192.168.1.1", nil, true},
+		{l2, "10.0.0.0", nil, true},
+		{l2, "10.0.0.0/8 192.168.1.254", nil, true},
+		{l2, "10.0.0.0/8 dev 192.168.1.254", nil, true},
+		{l2, "10.0.0.0/8 via 192.168.1", nil, true},
+		{l2, "10.0.0.0/8 via 2001:db8::1", nil, true},
+		{l2, "10.0.0.0/8 via 192.168.2.1", nil, true},
+		{l3, "2001:db8:1::/48 via 2001:db8::1", nil, true},
+	}
+	for _, tt := range tests {
+		routes, err := parseRoutes(tt.n, tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRoutes(%s, %q) error = %v, want error %t", tt.n.mode(), tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got := routeStrings(routes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseRoutes(%s, %q) = %q, want %q", tt.n.mode(), tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestMergeRoutes(t *testing.T) {
+	n := testNetwork(ipVlanL2, "192.168.1.0/24", "")
+	parse := func(spec string) []*route {
+		routes, err := parseRoutes(n, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return routes
+	}
+	connected := func(dst string) *staticRoute {
+		return &staticRoute{Destination: dst, RouteType: types.CONNECTED}
+	}
+	nextHop := func(dst, via string) *staticRoute {
+		return &staticRoute{Destination: dst, RouteType: types.NEXTHOP, NextHop: via}
+	}
+	tests := []struct {
+		name   string
+		routes []*staticRoute
+		sets   [][]*route
+		want   []*staticRoute
+	}{
+		{
+			name: "no routes",
+		},
+		{
+			name:   "network and endpoint routes",
+			routes: []*staticRoute{connected(defaultRoute)},
+			sets:   [][]*route{parse("10.0.0.0/8 via 192.168.1.254"), parse("172.16.0.0/12")},
+			want:   []*staticRoute{connected(defaultRoute), nextHop("10.0.0.0/8", "192.168.1.254"), connected("172.16.0.0/12")},
+		},
+		{
+			name: "endpoint routes replace network routes",
+			sets: [][]*route{parse("10.0.0.0/8 via 192.168.1.254,172.16.0.0/12"), parse("10.0.0.0/8 via 192.168.1.253")},
+			want: []*staticRoute{nextHop("10.0.0.0/8", "192.168.1.253"), connected("172.16.0.0/12")},
+		},
+		{
+			name:   "routes replace the join routes",
+			routes: []*staticRoute{connected("10.0.0.0/8")},
+			sets:   [][]*route{parse("10.0.0.0/8 via 192.168.1.254")},
+			want:   []*staticRoute{nextHop("10.0.0.0/8", "192.168.1.254")},
+		},
+	}
+	for _, tt := range tests {
+		if got := mergeRoutes(tt.routes, tt.sets...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: mergeRoutes() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
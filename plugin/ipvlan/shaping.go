@@ -0,0 +1,244 @@
+package ipvlan
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	egressRateOpt   = "egress_rate"
+	egressBurstOpt  = "egress_burst"
+	ingressRateOpt  = "ingress_rate"
+	ingressBurstOpt = "ingress_burst"
+	// tx queue length of shaped links created without one, the htb leaf
+	// queue is as long as the link queue and drops everything at zero
+	shapedTxQLen = 1000
+	ifbPrefix    = "ifb"
+	// the u32 redirect to the ifb device must run before any other filter
+	ifbFilterPrio = 1
+)
+
+// rateLimit holds the bandwidth limits of an endpoint link. Rates are in
+// bits per second and bursts in bytes, zero means unlimited.
+type rateLimit struct {
+	EgressRate   uint64 `json:",omitempty"`
+	EgressBurst  uint64 `json:",omitempty"`
+	IngressRate  uint64 `json:",omitempty"`
+	IngressBurst uint64 `json:",omitempty"`
+}
+
+// set reports whether any traffic of the endpoint is shaped
+func (l rateLimit) set() bool {
+	return l.EgressRate > 0 || l.IngressRate > 0
+}
+
+// parseRateLimit applies the rate and burst options returned by opt on top
+// of base, so Join options override the limits of the network
+func parseRateLimit(base rateLimit, opt func(key string) string) (rateLimit, error) {
+	l := base
+	for _, o := range []struct {
+		key   string
+		v     *uint64
+		parse func(string) (uint64, error)
+	}{
+		{egressRateOpt, &l.EgressRate, parseRate},
+		{egressBurstOpt, &l.EgressBurst, parseSize},
+		{ingressRateOpt, &l.IngressRate, parseRate},
+		{ingressBurstOpt, &l.IngressBurst, parseSize},
+	} {
+		s := opt(o.key)
+		if s == "" {
+			continue
+		}
+		v, err := o.parse(s)
+		if err != nil {
+			return l, fmt.Errorf("invalid %s [ %s ]: %s", o.key, s, err)
+		}
+		*o.v = v
+	}
+	if l.EgressBurst > 0 && l.EgressRate == 0 {
+		return l, fmt.Errorf("%s requires %s", egressBurstOpt, egressRateOpt)
+	}
+	if l.IngressBurst > 0 && l.IngressRate == 0 {
+		return l, fmt.Errorf("%s requires %s", ingressBurstOpt, ingressRateOpt)
+	}
+	return l, nil
+}
+
+// parseRate parses a tc style rate such as 100mbit or 10mbps into bits per
+// second, a bare number is in bits
+func parseRate(s string) (uint64, error) {
+	v, err := parseUnit(s, []unit{
+		{"gbit", 1e9}, {"mbit", 1e6}, {"kbit", 1e3}, {"bit", 1},
+		{"gbps", 8e9}, {"mbps", 8e6}, {"kbps", 8e3}, {"bps", 8},
+	})
+	if err != nil {
+		return 0, err
+	}
+	// the kernel rate spec holds bytes per second in 32 bits
+	if v == 0 || v/8 > math.MaxUint32 {
+		return 0, fmt.Errorf("rate must be between 8bit and %dbit", uint64(math.MaxUint32)*8)
+	}
+	return v, nil
+}
+
+// parseSize parses a size such as 32kb into bytes, a bare number is in bytes
+func parseSize(s string) (uint64, error) {
+	v, err := parseUnit(s, []unit{
+		{"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10},
+		{"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}, {"b", 1},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 || v > math.MaxUint32 {
+		return 0, fmt.Errorf("size must be between 1b and 4gb")
+	}
+	return v, nil
+}
+
+type unit struct {
+	suffix string
+	scale  float64
+}
+
+// parseUnit parses a number followed by one of the unit suffixes
+func parseUnit(s string, units []unit) (uint64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	scale := 1.0
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, scale = strings.TrimSuffix(s, u.suffix), u.scale
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("not a positive number with a unit")
+	}
+	return uint64(v * scale), nil
+}
+
+// ifbName returns the sandbox name of the ingress ifb device of an endpoint
+func ifbName(ifIndex int) string {
+	return ifbPrefix + strconv.Itoa(ifIndex)
+}
+
+// applyRateLimit shapes a link in the network namespace of the calling
+// thread. Egress is an htb root on the link itself, ingress traffic is
+// redirected to an ifb device that carries the htb instead.
+func applyRateLimit(link netlink.Link, ifIndex int, l rateLimit) error {
+	if l.EgressRate > 0 {
+		if err := addHtb(link, l.EgressRate, l.EgressBurst); err != nil {
+			return fmt.Errorf("unable to shape the egress of [ %s ]: %s", link.Attrs().Name, err)
+		}
+	}
+	if l.IngressRate == 0 {
+		return nil
+	}
+	name := ifbName(ifIndex)
+	ifb, err := netlink.LinkByName(name)
+	if err != nil {
+		attrs := netlink.NewLinkAttrs()
+		attrs.Name = name
+		if err := netlink.LinkAdd(&netlink.Ifb{LinkAttrs: attrs}); err != nil && err != syscall.EEXIST {
+			return fmt.Errorf("unable to create the ifb device [ %s ]: %s", name, err)
+		}
+		if ifb, err = netlink.LinkByName(name); err != nil {
+			return err
+		}
+	}
+	if err := netlink.LinkSetUp(ifb); err != nil {
+		return fmt.Errorf("unable to enable the ifb device [ %s ]: %s", name, err)
+	}
+	if err := addHtb(ifb, l.IngressRate, l.IngressBurst); err != nil {
+		return fmt.Errorf("unable to shape the ingress of [ %s ]: %s", link.Attrs().Name, err)
+	}
+	ingress := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+	if err := netlink.QdiscReplace(ingress); err != nil {
+		return fmt.Errorf("unable to add the ingress qdisc to [ %s ]: %s", link.Attrs().Name, err)
+	}
+	redirect := &netlink.U32{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    netlink.MakeHandle(0xffff, 0),
+			Priority:  ifbFilterPrio,
+			Protocol:  syscall.ETH_P_ALL,
+		},
+		RedirIndex: ifb.Attrs().Index,
+	}
+	if err := netlink.FilterAdd(redirect); err != nil && err != syscall.EEXIST {
+		return fmt.Errorf("unable to redirect the ingress of [ %s ] to [ %s ]: %s", link.Attrs().Name, name, err)
+	}
+	return nil
+}
+
+// addHtb replaces the root qdisc of a link with an htb whose default class
+// carries the rate
+func addHtb(link netlink.Link, rate, burst uint64) error {
+	qdisc := netlink.NewHtb(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(1, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	})
+	qdisc.Defcls = 1
+	if err := netlink.QdiscReplace(qdisc); err != nil {
+		return err
+	}
+	class := netlink.NewHtbClass(netlink.ClassAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(1, 1),
+		Parent:    netlink.MakeHandle(1, 0),
+	}, netlink.HtbClassAttrs{
+		Rate:   rate,
+		Buffer: uint32(burst),
+	})
+	return netlink.ClassReplace(class)
+}
+
+// shapeEndpoint applies the limits of a joined endpoint once its link is in
+// the sandbox, the qdiscs of a link are dropped when it changes namespace
+func shapeEndpoint(n *network, eid string) {
+	ep := n.endpointCopy(eid)
+	if ep == nil || !ep.limits.set() {
+		return
+	}
+	onSandboxLink(ep, "rate limits", func(link netlink.Link) error {
+		if err := applyRateLimit(link, ep.ifIndex, ep.limits); err != nil {
+			return err
+		}
+		log.Infof("Applied the rate limits egress [ %d ] ingress [ %d ] bit/s to endpoint [ %s ]",
+			ep.limits.EgressRate, ep.limits.IngressRate, eid)
+		return nil
+	})
+}
+
+// removeIfb deletes the ingress ifb device of an endpoint leaving its
+// sandbox, the device would otherwise live as long as the container
+func removeIfb(ep *endpoint) {
+	if ep.limits.IngressRate == 0 || ep.sandboxKey == "" {
+		return
+	}
+	err := withNetns(ep.sandboxKey, func() error {
+		ifb, err := netlink.LinkByName(ifbName(ep.ifIndex))
+		if err != nil {
+			return nil
+		}
+		return netlink.LinkDel(ifb)
+	})
+	if err != nil {
+		log.Debugf("Unable to delete the ifb device of endpoint [ %s ]: %s", ep.id, err)
+	}
+}
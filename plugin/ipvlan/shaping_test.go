@@ -0,0 +1,108 @@
+package ipvlan
+
+import "testing"
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    uint64
+		wantErr bool
+	}{
+		{"100mbit", 100e6, false},
+		{"1Gbit", 1e9, false},
+		{" 1.5kbit ", 1500, false},
+		{"64bit", 64, false},
+		{"1000", 1000, false},
+		{"10mbps", 80e6, false},
+		{"2kbps", 16e3, false},
+		{"8bps", 64, false},
+		{"34gbit", 34e9, false},
+		{"35gbit", 0, true},
+		{"0mbit", 0, true},
+		{"-1mbit", 0, true},
+		{"fast", 0, true},
+		{"mbit", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseRate(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseRate(%q) = %d, %v, want %d, error %t", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    uint64
+		wantErr bool
+	}{
+		{"32kb", 32 << 10, false},
+		{"32k", 32 << 10, false},
+		{"1.5mb", 3 << 19, false},
+		{"2M", 2 << 20, false},
+		{"1gb", 1 << 30, false},
+		{"1500b", 1500, false},
+		{"1500", 1500, false},
+		{"4gb", 0, true},
+		{"0kb", 0, true},
+		{"kb", 0, true},
+		{"big", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.s)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSize(%q) = %d, %v, want %d, error %t", tt.s, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	network := rateLimit{EgressRate: 100e6, EgressBurst: 64 << 10}
+	tests := []struct {
+		name    string
+		base    rateLimit
+		opts    map[string]string
+		want    rateLimit
+		wantErr bool
+	}{
+		{
+			name: "no options",
+		},
+		{
+			name: "all options",
+			opts: map[string]string{egressRateOpt: "10mbit", egressBurstOpt: "32kb", ingressRateOpt: "20mbit", ingressBurstOpt: "16kb"},
+			want: rateLimit{EgressRate: 10e6, EgressBurst: 32 << 10, IngressRate: 20e6, IngressBurst: 16 << 10},
+		},
+		{
+			name: "endpoint options override the network",
+			base: network,
+			opts: map[string]string{egressRateOpt: "10mbit", ingressRateOpt: "1mbit"},
+			want: rateLimit{EgressRate: 10e6, EgressBurst: 64 << 10, IngressRate: 1e6},
+		},
+		{
+			name: "network burst with an endpoint rate",
+			base: rateLimit{EgressBurst: 64 << 10},
+			opts: map[string]string{egressRateOpt: "1mbit"},
+			want: rateLimit{EgressRate: 1e6, EgressBurst: 64 << 10},
+		},
+		{name: "egress burst without rate", opts: map[string]string{egressBurstOpt: "32kb"}, wantErr: true},
+		{name: "ingress burst without rate", opts: map[string]string{ingressBurstOpt: "32kb"}, wantErr: true},
+		{name: "bad rate", opts: map[string]string{ingressRateOpt: "1 mbit/s"}, wantErr: true},
+		{name: "bad burst", base: network, opts: map[string]string{egressBurstOpt: "lots"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRateLimit(tt.base, func(key string) string { return tt.opts[key] })
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseRateLimit() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("%s: parseRateLimit() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if (rateLimit{EgressBurst: 1}).set() || !(rateLimit{IngressRate: 1}).set() {
+		t.Errorf("set() must only report limits with a rate")
+	}
+}
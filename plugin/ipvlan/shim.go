@@ -0,0 +1,182 @@
+package ipvlan
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// aux address name reserving the shim address from ipam,
+	// e.g. --aux-address host_shim=192.168.1.250
+	hostShimAuxKey = "host_shim"
+	shimLinkPrefix = "ivh"
+)
+
+// shimName returns the default namespace name of a network's host shim link
+func shimName(nid string) string {
+	n := maxIfaceNameLen - len(shimLinkPrefix)
+	if len(nid) < n {
+		n = len(nid)
+	}
+	return shimLinkPrefix + nid[:n]
+}
+
+// parseHostShim validates -o host_shim and picks the shim address. The
+// host_shim aux address wins, other aux addresses belong to other devices
+// of the LAN and are never used. Without it the last usable address of the
+// subnet is only taken when the plugin IPAM pool excludes it, any other IPAM
+// would hand it out to a container.
+func parseHostShim(n *network, shimOpt string, aux map[string]net.IP, pools *ipam) error {
+	if shimOpt == "" {
+		return nil
+	}
+	shim, err := strconv.ParseBool(shimOpt)
+	if err != nil {
+		return fmt.Errorf("invalid host_shim [ %s ], must be true or false", shimOpt)
+	}
+	if !shim {
+		return nil
+	}
+	if !n.l2() {
+		return fmt.Errorf("host_shim requires an l2 or macvlan network, l3 networks are reached through the host routes")
+	}
+	if n.ipvlanFlag == ipvlanFlagPrivate {
+		return fmt.Errorf("host_shim cannot reach the endpoints of an ipvlan_flag=private network")
+	}
+	if n.cidr == nil {
+		return fmt.Errorf("host_shim requires an IPv4 subnet")
+	}
+	ip := aux[hostShimAuxKey]
+	if ip == nil {
+		last := lastHostAddr(n.cidr)
+		if pools == nil || !pools.excludes(n.cidr, last) {
+			return fmt.Errorf("host_shim requires an address reserved from ipam, pass --aux-address %s=%s or exclude it from the plugin ipam pool", hostShimAuxKey, last)
+		}
+		ip = last
+	}
+	if ip = ip.To4(); ip == nil || !n.cidr.Contains(ip) {
+		return fmt.Errorf("host_shim address [ %s ] is not in the subnet [ %s ]", ip, n.cidr)
+	}
+	n.hostShim = true
+	n.shimIP = ip
+	return nil
+}
+
+// lastHostAddr returns the address before the broadcast address of a subnet
+func lastHostAddr(subnet *net.IPNet) net.IP {
+	ip := make(net.IP, net.IPv4len)
+	base := subnet.IP.To4()
+	for i := range ip {
+		ip[i] = base[i] | ^subnet.Mask[i]
+	}
+	ip[net.IPv4len-1]--
+	return ip
+}
+
+// ensureHostShim creates the default namespace child link of a host_shim
+// network and its host routes to the endpoints. ipvlan L2 and macvlan
+// children cannot reach the parent's own address, the shim gives the host a
+// sibling address.
+func ensureHostShim(n *network) error {
+	if !n.hostShim {
+		return nil
+	}
+	name := shimName(n.id)
+	shim, err := netlink.LinkByName(name)
+	if err != nil {
+		parent, err := netlink.LinkByName(n.ifaceOpt)
+		if err != nil {
+			return fmt.Errorf("parent interface [ %s ] not found: %s", n.ifaceOpt, err)
+		}
+		attrs := netlink.LinkAttrs{
+			Name:        name,
+			ParentIndex: parent.Attrs().Index,
+			MTU:         n.linkMTU(parent),
+		}
+		// the shim must be of the same kind as the containers sharing the parent
+		shim = newIPVlan(n, attrs, netlink.IPVLAN_MODE_L2)
+		if n.macvlan() {
+			shim = &netlink.Macvlan{LinkAttrs: attrs, Mode: netlink.MACVLAN_MODE_BRIDGE}
+		}
+		if err := addLink(shim); err != nil {
+			return fmt.Errorf("unable to create the host shim [ %s ] on [ %s ]: %s", name, n.ifaceOpt, err)
+		}
+		log.Infof("Created the host shim [ %s ] with the address [ %s ] for network [ %s ]", name, n.shimIP, n.id)
+	}
+	if err := netlink.LinkSetUp(shim); err != nil {
+		return fmt.Errorf("unable to enable the host shim [ %s ]: %s", name, err)
+	}
+	// a host address keeps the kernel from adding a subnet route that
+	// would conflict with the parent's own connected route
+	addr := &netlink.Addr{IPNet: &net.IPNet{IP: n.shimIP, Mask: net.CIDRMask(32, 32)}}
+	if err := netlink.AddrAdd(shim, addr); err != nil && err != syscall.EEXIST {
+		return fmt.Errorf("unable to add the address [ %s ] to the host shim [ %s ]: %s", n.shimIP, name, err)
+	}
+	n.Lock()
+	var addrs []net.IP
+	for _, ep := range n.endpoints {
+		if ep.addr != nil {
+			addrs = append(addrs, ep.addr.IP)
+		}
+	}
+	n.Unlock()
+	for _, ip := range addrs {
+		if err := addShimRoute(n, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addShimRoute routes an endpoint address of a host_shim network through the shim
+func addShimRoute(n *network, ip net.IP) error {
+	if !n.hostShim || ip.To4() == nil {
+		return nil
+	}
+	shim, err := netlink.LinkByName(shimName(n.id))
+	if err != nil {
+		return fmt.Errorf("host shim of network [ %s ] not found: %s", n.id, err)
+	}
+	route := &netlink.Route{
+		LinkIndex: shim.Attrs().Index,
+		Scope:     netlink.SCOPE_LINK,
+		Dst:       &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)},
+		Src:       n.shimIP,
+	}
+	if err := netlink.RouteAdd(route); err != nil && err != syscall.EEXIST {
+		return fmt.Errorf("unable to add the host route to [ %s ] through the host shim: %s", ip, err)
+	}
+	return nil
+}
+
+// delShimRoute removes the host route of an endpoint, a missing route is not an error
+func delShimRoute(n *network, ip net.IP) {
+	if !n.hostShim || ip.To4() == nil {
+		return
+	}
+	shim, err := netlink.LinkByName(shimName(n.id))
+	if err != nil {
+		return
+	}
+	route := &netlink.Route{
+		LinkIndex: shim.Attrs().Index,
+		Scope:     netlink.SCOPE_LINK,
+		Dst:       &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)},
+	}
+	if err := netlink.RouteDel(route); err != nil && err != syscall.ESRCH {
+		log.Warnf("Unable to delete the host route to [ %s ] through the host shim: %s", ip, err)
+	}
+}
+
+// removeHostShim deletes the shim link of a network along with its routes
+func removeHostShim(n *network) error {
+	if !n.hostShim {
+		return nil
+	}
+	return deleteLink(shimName(n.id))
+}
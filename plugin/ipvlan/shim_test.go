@@ -0,0 +1,66 @@
+package ipvlan
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLastHostAddr(t *testing.T) {
+	tests := []struct {
+		subnet, want string
+	}{
+		{"192.168.1.0/24", "192.168.1.254"},
+		{"10.0.0.0/8", "10.255.255.254"},
+		{"172.16.4.64/26", "172.16.4.126"},
+		{"192.168.1.0/30", "192.168.1.2"},
+	}
+	for _, tt := range tests {
+		_, subnet, _ := net.ParseCIDR(tt.subnet)
+		if got := lastHostAddr(subnet); got.String() != tt.want {
+			t.Errorf("lastHostAddr(%s) = %s, want %s", tt.subnet, got, tt.want)
+		}
+	}
+}
+
+func TestParseHostShim(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.1.0/24")
+	excluding := &ipam{pools: map[string]*pool{
+		"p1": {id: "p1", subnet: subnet, excluded: []addrRange{{net.ParseIP("192.168.1.250").To4(), net.ParseIP("192.168.1.254").To4()}}},
+	}}
+	tests := []struct {
+		name    string
+		mode    string
+		opt     string
+		aux     map[string]net.IP
+		pools   *ipam
+		want    string
+		wantErr bool
+	}{
+		{"disabled", ipVlanL2, "false", nil, nil, "", false},
+		{"named aux", ipVlanL2, "true", map[string]net.IP{"router": net.ParseIP("192.168.1.2"), hostShimAuxKey: net.ParseIP("192.168.1.250")}, nil, "192.168.1.250", false},
+		{"other aux", ipVlanL2, "true", map[string]net.IP{"router": net.ParseIP("192.168.1.1")}, nil, "", true},
+		{"other aux with ipam exclusion", ipVlanL2, "true", map[string]net.IP{"router": net.ParseIP("192.168.1.1")}, excluding, "192.168.1.254", false},
+		{"ipam exclusion", ipVlanL2, "true", nil, excluding, "192.168.1.254", false},
+		{"no reservation", ipVlanL2, "true", nil, nil, "", true},
+		{"unexcluded pool", ipVlanL2, "true", nil, &ipam{pools: map[string]*pool{}}, "", true},
+		{"ambiguous aux", ipVlanL2, "true", map[string]net.IP{"a": net.ParseIP("192.168.1.2"), "b": net.ParseIP("192.168.1.3")}, nil, "", true},
+		{"outside subnet", ipVlanL2, "true", map[string]net.IP{hostShimAuxKey: net.ParseIP("10.0.0.1")}, nil, "", true},
+		{"l3", ipVlanL3, "true", map[string]net.IP{hostShimAuxKey: net.ParseIP("192.168.1.250")}, nil, "", true},
+		{"invalid", ipVlanL2, "on", nil, nil, "", true},
+	}
+	for _, tt := range tests {
+		n := &network{id: "net1", modeOpt: tt.mode, cidr: subnet}
+		err := parseHostShim(n, tt.opt, tt.aux, tt.pools)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		var got string
+		if n.shimIP != nil {
+			got = n.shimIP.String()
+		}
+		if got != tt.want || n.hostShim != (tt.want != "") {
+			t.Errorf("%s: shim [ %t %s ], want [ %s ]", tt.name, n.hostShim, got, tt.want)
+		}
+	}
+}
@@ -2,21 +2,59 @@ package ipvlan
 
 import (
 	"fmt"
+	"net"
+	"os"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/libnetwork/types"
-	"net"
 )
 
 type network struct {
 	id        string
 	endpoints endpointTable
 	gateway   string
+	gatewayV6 string
 	ifaceOpt  string
 	modeOpt   string
+	// driverKindIpvlan or driverKindMacvlan, the socket the network was created on
+	kind string
+	// ipvlan link tuning, an mtu of 0 inherits the parent MTU
+	mtu        int
+	txQueueLen int
+	// outbound NAT of the IPv4 subnet, masquerading unless snatIP is set
+	nat    bool
+	snatIP net.IP
+	// default namespace ipvlan child giving the host a path to l2 endpoints
+	hostShim bool
+	shimIP   net.IP
+	// static routes handed to every endpoint at join
+	routes []*route
+	// slaves and mode of a bond parent the plugin builds on demand
+	bondSlaves []string
+	bondMode   string
+	// ipvlan port isolation flag, empty for the kernel default bridge
+	ipvlanFlag string
+	// bandwidth limits of the endpoint links, Join options override them
+	limits rateLimit
+	// endpoint addresses are leased from a DHCP server on the parent LAN
+	dhcp bool
+	// duplicate address detection of the endpoints, empty when off
+	dad string
+	// gratuitous ARPs and unsolicited neighbor advertisements sent at join
+	announceCount    int
+	announceInterval time.Duration
+	// why the parent cannot carry traffic, empty while it is healthy
+	degraded    string
+	parentIndex int
+	// serializes the parent watcher restoring the host routes, shim and
+	// prefix with the teardown of the network, which sets deleting
+	hostLock sync.Mutex
+	deleting bool
 	sync.Mutex
-	cidr *net.IPNet
+	cidr   *net.IPNet
+	cidrV6 *net.IPNet
 }
 
 type networkTable map[string]*network
@@ -36,6 +74,53 @@ func (d *driver) getNetwork(id string) (*network, error) {
 	return nil, types.NotFoundErrorf("network not found: %s", id)
 }
 
+// mode returns the ipvlan or macvlan mode stored with the network
+func (n *network) mode() string {
+	return n.modeOpt
+}
+
+// pinMode stores the plugin wide --mode on a network created without -o mode,
+// so a later --mode change does not alter the network
+func (n *network) pinMode(pluginMode string) {
+	if n.modeOpt != "" {
+		return
+	}
+	n.modeOpt = pluginMode
+	if n.macvlan() {
+		n.modeOpt = macvlanBridge
+	}
+}
+
+// subnets returns the IPv4 and IPv6 pools of a dual-stack network
+func (n *network) subnets() []*net.IPNet {
+	var subnets []*net.IPNet
+	for _, subnet := range []*net.IPNet{n.cidr, n.cidrV6} {
+		if subnet != nil {
+			subnets = append(subnets, subnet)
+		}
+	}
+	return subnets
+}
+
+// liveEndpointCount returns the number of endpoints joined to a sandbox that
+// still exists. Endpoints whose container is gone, or that were learned from
+// the daemon and never deleted, do not keep the network alive.
+func (n *network) liveEndpointCount() int {
+	n.Lock()
+	defer n.Unlock()
+
+	count := 0
+	for _, ep := range n.endpoints {
+		if ep.sandboxKey == "" {
+			continue
+		}
+		if _, err := os.Stat(ep.sandboxKey); err == nil {
+			count++
+		}
+	}
+	return count
+}
+
 func (n *network) endpoint(eid string) *endpoint {
 	n.Lock()
 	defer n.Unlock()
@@ -49,6 +134,64 @@ func (n *network) addEndpoint(ep *endpoint) {
 	n.Unlock()
 }
 
+// endpointCopy returns a snapshot of an endpoint safe to read without the lock
+func (n *network) endpointCopy(eid string) *endpoint {
+	n.Lock()
+	defer n.Unlock()
+
+	ep, ok := n.endpoints[eid]
+	if !ok {
+		return nil
+	}
+	c := *ep
+	return &c
+}
+
+// setEndpointSandbox records the sandbox an endpoint joined, an empty
+// sandboxKey marks it as left
+func (n *network) setEndpointSandbox(eid, sandboxKey string, ifIndex int) {
+	n.Lock()
+	defer n.Unlock()
+
+	if ep, ok := n.endpoints[eid]; ok {
+		ep.sandboxKey = sandboxKey
+		ep.ifIndex = ifIndex
+	}
+}
+
+// setEndpointPortMap records the published ports of an endpoint
+func (n *network) setEndpointPortMap(eid string, portMap []types.PortBinding) {
+	n.Lock()
+	defer n.Unlock()
+
+	if ep, ok := n.endpoints[eid]; ok {
+		ep.portMap = portMap
+	}
+}
+
+// setEndpointLease records the DHCP lease of an endpoint, nil once released
+func (n *network) setEndpointLease(eid string, lease *dhcpLease) {
+	n.Lock()
+	defer n.Unlock()
+
+	if ep, ok := n.endpoints[eid]; ok {
+		ep.lease = lease
+	}
+}
+
+// endpointCopies returns a snapshot of every endpoint of the network
+func (n *network) endpointCopies() []*endpoint {
+	n.Lock()
+	defer n.Unlock()
+
+	eps := make([]*endpoint, 0, len(n.endpoints))
+	for _, ep := range n.endpoints {
+		c := *ep
+		eps = append(eps, &c)
+	}
+	return eps
+}
+
 func (n *network) deleteEndpoint(eid string) {
 	n.Lock()
 	delete(n.endpoints, eid)
@@ -81,7 +224,12 @@ func (d *driver) network(nid string) *network {
 func (d *driver) addNetwork(n *network) {
 	d.Lock()
 	d.networks[n.id] = n
+	// the network table keeps the parent links from now on
+	d.dropLinkHolds(n.id)
 	d.Unlock()
+	if n.dhcp {
+		d.startLeaseRenewal()
+	}
 }
 
 func (d *driver) delNetwork(nid string) {
@@ -90,6 +238,31 @@ func (d *driver) delNetwork(nid string) {
 	d.Unlock()
 }
 
+// parentModeConflict returns a network on the same parent interface whose
+// kernel ipvlan mode or port flag differs, both are properties of the parent port.
+func (d *driver) parentModeConflict(n *network) *network {
+	want, _ := setIpVlanMode(n.mode())
+	for _, other := range d.getNetworks() {
+		if other.id == n.id || other.ifaceOpt != n.ifaceOpt {
+			continue
+		}
+		// a parent cannot carry macvlan and ipvlan children at once
+		if other.driverKind() != n.driverKind() {
+			return other
+		}
+		if n.macvlan() {
+			continue
+		}
+		if mode, _ := setIpVlanMode(other.mode()); mode != want {
+			return other
+		}
+		if other.portFlag() != n.portFlag() {
+			return other
+		}
+	}
+	return nil
+}
+
 // Safely return a slice of existng networks
 func (d *driver) getNetworks() []*network {
 	d.Lock()
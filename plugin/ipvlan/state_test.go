@@ -0,0 +1,94 @@
+package ipvlan
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLiveEndpointCount(t *testing.T) {
+	sandbox, err := ioutil.TempFile("", "sandbox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sandbox.Close()
+	defer os.Remove(sandbox.Name())
+
+	n := &network{id: "net1", endpoints: endpointTable{
+		"joined":  {id: "joined", sandboxKey: sandbox.Name()},
+		"created": {id: "created"},
+		"gone":    {id: "gone", sandboxKey: sandbox.Name() + "-removed"},
+	}}
+	if got := n.liveEndpointCount(); got != 1 {
+		t.Errorf("liveEndpointCount() = %d, want 1", got)
+	}
+	n.setEndpointSandbox("joined", "", 0)
+	if got := n.liveEndpointCount(); got != 0 {
+		t.Errorf("liveEndpointCount() after leave = %d, want 0", got)
+	}
+}
+
+func TestPinMode(t *testing.T) {
+	tests := []struct {
+		kind, modeOpt, pluginMode string
+		want                      string
+	}{
+		{driverKindIpvlan, "", ipVlanL3, ipVlanL3},
+		{driverKindIpvlan, ipVlanL2, ipVlanL3, ipVlanL2},
+		{driverKindIpvlan, "", ipVlanL3S, ipVlanL3S},
+		{driverKindMacvlan, "", ipVlanL3, macvlanBridge},
+		{driverKindMacvlan, macvlanVepa, ipVlanL2, macvlanVepa},
+	}
+	for _, tt := range tests {
+		n := &network{id: "net1", kind: tt.kind, modeOpt: tt.modeOpt}
+		n.pinMode(tt.pluginMode)
+		if got := n.mode(); got != tt.want {
+			t.Errorf("pinMode(%s %q, %s) mode = %s, want %s", tt.kind, tt.modeOpt, tt.pluginMode, got, tt.want)
+		}
+		// the stored mode no longer follows the plugin mode
+		n.pinMode(ipVlanL3Routing)
+		if got := n.mode(); got != tt.want {
+			t.Errorf("pinMode(%s %q) again mode = %s, want %s", tt.kind, tt.modeOpt, got, tt.want)
+		}
+	}
+}
+
+func TestParentModeConflict(t *testing.T) {
+	existing := []*network{
+		{id: "l2", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth1"},
+		{id: "l3", kind: driverKindIpvlan, modeOpt: ipVlanL3, ifaceOpt: "eth2"},
+		{id: "private", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth3", ipvlanFlag: ipvlanFlagPrivate},
+		{id: "macvlan", kind: driverKindMacvlan, modeOpt: macvlanBridge, ifaceOpt: "eth4"},
+	}
+	tests := []struct {
+		name string
+		n    *network
+		want string
+	}{
+		{"same mode", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth1"}, ""},
+		{"explicit bridge flag", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth1", ipvlanFlag: ipvlanFlagBridge}, ""},
+		{"other parent", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL3S, ifaceOpt: "eth5"}, ""},
+		{"other mode", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL3, ifaceOpt: "eth1"}, "l2"},
+		{"l3routing shares the l3 kernel mode", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL3Routing, ifaceOpt: "eth2"}, ""},
+		{"l3s", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL3S, ifaceOpt: "eth2"}, "l3"},
+		{"other flag", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth3"}, "private"},
+		{"same flag", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth3", ipvlanFlag: ipvlanFlagPrivate}, ""},
+		{"ipvlan on a macvlan parent", &network{id: "new", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth4"}, "macvlan"},
+		{"macvlan on an ipvlan parent", &network{id: "new", kind: driverKindMacvlan, modeOpt: macvlanBridge, ifaceOpt: "eth1"}, "l2"},
+		{"macvlan modes", &network{id: "new", kind: driverKindMacvlan, modeOpt: macvlanVepa, ifaceOpt: "eth4"}, ""},
+		{"itself", &network{id: "l2", kind: driverKindIpvlan, modeOpt: ipVlanL3, ifaceOpt: "eth1"}, ""},
+	}
+	d := &driver{networks: networkTable{}}
+	for _, n := range existing {
+		d.addNetwork(n)
+	}
+	for _, tt := range tests {
+		got := ""
+		if other := d.parentModeConflict(tt.n); other != nil {
+			got = other.id
+		}
+		if got != tt.want {
+			t.Errorf("%s: parentModeConflict() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package ipvlan
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/libnetwork/types"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	stateFile    = "state.json"
+	stateVersion = 1
+)
+
+// stateStore persists the driver network and endpoint tables so that
+// networks created before a plugin restart can still be joined.
+type stateStore struct {
+	path string
+	sync.Mutex
+}
+
+// driverState is the on-disk representation of the driver tables
+type driverState struct {
+	Version  int
+	Networks []*networkState
+	// links such as vlan sub-interfaces the plugin created and must remove
+	OwnedLinks []string
+	// address pools of the plugin IPAM driver
+	Pools []*poolState
+}
+
+type poolState struct {
+	ID           string
+	AddressSpace string
+	Subnet       string
+	IPRange      string
+	Gateway      string
+	Excluded     []string
+	Allocated    []string
+}
+
+type networkState struct {
+	ID         string
+	IfaceOpt   string
+	ModeOpt    string
+	Kind       string
+	Cidr       string
+	Gateway    string
+	CidrV6     string
+	GatewayV6  string
+	MTU        int
+	TxQueueLen int
+	Nat        bool
+	SnatIP     string
+	HostShim   bool
+	ShimIP     string
+	Routes     []string
+	BondSlaves []string
+	BondMode   string
+	IpvlanFlag string
+	Limits     rateLimit
+	Dhcp       bool
+	Dad        string
+	Endpoints  []*endpointState
+	// AnnounceInterval is only unset in the state of older plugins
+	AnnounceCount    int
+	AnnounceInterval time.Duration
+}
+
+type endpointState struct {
+	ID      string
+	Mac     string
+	Addr    string
+	AddrV6  string
+	SrcName string
+	Routes  []string
+	// set while the endpoint is joined to a sandbox
+	SandboxKey string
+	IfIndex    int
+	Limits     rateLimit
+	PortMap    []types.PortBinding
+	Lease      *dhcpLease `json:",omitempty"`
+}
+
+func newStateStore(dir string) (*stateStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create the state directory [ %s ]: %s", dir, err)
+	}
+	return &stateStore{path: filepath.Join(dir, stateFile)}, nil
+}
+
+// load reads the persisted state, an absent state file is an empty state
+func (s *stateStore) load() (*driverState, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	state := &driverState{Version: stateVersion}
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to decode the state file [ %s ]: %s", s.path, err)
+	}
+	if state.Version != stateVersion {
+		return nil, fmt.Errorf("unsupported state file version [ %d ] in [ %s ]", state.Version, s.path)
+	}
+	return state, nil
+}
+
+// save atomically replaces the state file, the caller holds the store lock
+func (s *stateStore) save(state *driverState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), stateFile)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	// fsync the directory so the rename itself survives a crash
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// networkTable rebuilds the driver networks, invalid records are skipped
+func (state *driverState) networkTable() networkTable {
+	networks := networkTable{}
+	for _, ns := range state.Networks {
+		n, err := ns.network()
+		if err != nil {
+			log.Warnf("Skipping the persisted network [ %s ]: %s", ns.ID, err)
+			continue
+		}
+		networks[n.id] = n
+	}
+	return networks
+}
+
+// poolTable rebuilds the IPAM pools, invalid records are skipped
+func (state *driverState) poolTable() map[string]*pool {
+	pools := map[string]*pool{}
+	for _, ps := range state.Pools {
+		p, err := ps.pool()
+		if err != nil {
+			log.Warnf("Skipping the persisted pool [ %s ]: %s", ps.ID, err)
+			continue
+		}
+		pools[p.id] = p
+	}
+	return pools
+}
+
+// state returns a serializable copy of the pools, sorted by ID
+func (m *ipam) state() []*poolState {
+	m.Lock()
+	defer m.Unlock()
+
+	var pools []*poolState
+	for _, p := range m.pools {
+		ps := &poolState{
+			ID:           p.id,
+			AddressSpace: p.space,
+			Subnet:       p.subnet.String(),
+		}
+		if p.ipRange != nil {
+			ps.IPRange = p.ipRange.String()
+		}
+		if p.gateway != nil {
+			ps.Gateway = p.gateway.String()
+		}
+		for _, r := range p.excluded {
+			ps.Excluded = append(ps.Excluded, r.String())
+		}
+		for ip := range p.allocated {
+			ps.Allocated = append(ps.Allocated, ip)
+		}
+		sort.Strings(ps.Allocated)
+		pools = append(pools, ps)
+	}
+	sort.Sort(poolsByID(pools))
+	return pools
+}
+
+type poolsByID []*poolState
+
+func (p poolsByID) Len() int           { return len(p) }
+func (p poolsByID) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p poolsByID) Less(i, j int) bool { return p[i].ID < p[j].ID }
+
+// pool rebuilds a pool from its persisted record
+func (ps *poolState) pool() (*pool, error) {
+	_, subnet, err := net.ParseCIDR(ps.Subnet)
+	if err != nil {
+		return nil, err
+	}
+	subnet.IP = normalizeIP(subnet.IP)
+	p := &pool{
+		id:        ps.ID,
+		space:     ps.AddressSpace,
+		subnet:    subnet,
+		allocated: map[string]bool{},
+	}
+	if ps.IPRange != "" {
+		_, ipRange, err := net.ParseCIDR(ps.IPRange)
+		if err != nil {
+			return nil, err
+		}
+		ipRange.IP = normalizeIP(ipRange.IP)
+		p.ipRange = ipRange
+	}
+	if ps.Gateway != "" {
+		if p.gateway = net.ParseIP(ps.Gateway); p.gateway == nil {
+			return nil, fmt.Errorf("invalid gateway [ %s ]", ps.Gateway)
+		}
+		p.gateway = normalizeIP(p.gateway)
+	}
+	if p.excluded, err = parseExcluded(subnet, strings.Join(ps.Excluded, ",")); err != nil {
+		return nil, err
+	}
+	for _, s := range ps.Allocated {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid allocated address [ %s ]", s)
+		}
+		p.allocated[normalizeIP(ip).String()] = true
+	}
+	return p, nil
+}
+
+// ownedLinks returns the set of links the plugin created
+func (state *driverState) ownedLinks() map[string]bool {
+	links := map[string]bool{}
+	for _, name := range state.OwnedLinks {
+		links[name] = true
+	}
+	return links
+}
+
+// state returns a serializable copy of the network and its endpoints
+func (n *network) state() *networkState {
+	n.Lock()
+	defer n.Unlock()
+
+	ns := &networkState{
+		ID:         n.id,
+		IfaceOpt:   n.ifaceOpt,
+		ModeOpt:    n.modeOpt,
+		Kind:       n.kind,
+		Gateway:    n.gateway,
+		GatewayV6:  n.gatewayV6,
+		MTU:        n.mtu,
+		TxQueueLen: n.txQueueLen,
+		Nat:        n.nat,
+		HostShim:   n.hostShim,
+		Routes:     routeStrings(n.routes),
+		BondSlaves: n.bondSlaves,
+		BondMode:   n.bondMode,
+		IpvlanFlag: n.ipvlanFlag,
+		Limits:     n.limits,
+		Dhcp:       n.dhcp,
+		Dad:        n.dad,
+	}
+	ns.AnnounceCount, ns.AnnounceInterval = n.announceCount, n.announceInterval
+	if n.snatIP != nil {
+		ns.SnatIP = n.snatIP.String()
+	}
+	if n.shimIP != nil {
+		ns.ShimIP = n.shimIP.String()
+	}
+	if n.cidr != nil {
+		ns.Cidr = n.cidr.String()
+	}
+	if n.cidrV6 != nil {
+		ns.CidrV6 = n.cidrV6.String()
+	}
+	for _, ep := range n.endpoints {
+		es := &endpointState{
+			ID:         ep.id,
+			SrcName:    ep.srcName,
+			Routes:     routeStrings(ep.routes),
+			SandboxKey: ep.sandboxKey,
+			IfIndex:    ep.ifIndex,
+			Limits:     ep.limits,
+			PortMap:    ep.portMap,
+			Lease:      ep.lease,
+		}
+		if ep.mac != nil {
+			es.Mac = ep.mac.String()
+		}
+		if ep.addr != nil {
+			es.Addr = ep.addr.String()
+		}
+		if ep.addrV6 != nil {
+			es.AddrV6 = ep.addrV6.String()
+		}
+		ns.Endpoints = append(ns.Endpoints, es)
+	}
+	return ns
+}
+
+// network rebuilds a driver network from its persisted representation
+func (ns *networkState) network() (*network, error) {
+	if ns.ID == "" {
+		return nil, fmt.Errorf("missing network id")
+	}
+	n := &network{
+		id:         ns.ID,
+		endpoints:  endpointTable{},
+		ifaceOpt:   ns.IfaceOpt,
+		modeOpt:    ns.ModeOpt,
+		kind:       ns.Kind,
+		gateway:    ns.Gateway,
+		gatewayV6:  ns.GatewayV6,
+		mtu:        ns.MTU,
+		txQueueLen: ns.TxQueueLen,
+		nat:        ns.Nat,
+		snatIP:     net.ParseIP(ns.SnatIP).To4(),
+		hostShim:   ns.HostShim,
+		shimIP:     net.ParseIP(ns.ShimIP).To4(),
+		bondSlaves: ns.BondSlaves,
+		bondMode:   ns.BondMode,
+		ipvlanFlag: ns.IpvlanFlag,
+		limits:     ns.Limits,
+		dhcp:       ns.Dhcp,
+		dad:        ns.Dad,
+	}
+	n.announceCount, n.announceInterval = ns.AnnounceCount, ns.AnnounceInterval
+	if n.announceInterval == 0 {
+		if err := parseAnnounceOpts(n, "", ""); err != nil {
+			return nil, err
+		}
+	}
+	if ns.Cidr != "" {
+		_, cidr, err := net.ParseCIDR(ns.Cidr)
+		if err != nil {
+			return nil, err
+		}
+		n.cidr = cidr
+	}
+	if ns.CidrV6 != "" {
+		_, cidr, err := net.ParseCIDR(ns.CidrV6)
+		if err != nil {
+			return nil, err
+		}
+		n.cidrV6 = cidr
+	}
+	if n.hostShim && n.shimIP == nil {
+		return nil, fmt.Errorf("invalid host shim address [ %s ]", ns.ShimIP)
+	}
+	var err error
+	if n.routes, err = parseRoutes(n, strings.Join(ns.Routes, ",")); err != nil {
+		return nil, err
+	}
+	for _, es := range ns.Endpoints {
+		ep := &endpoint{
+			id:         es.ID,
+			srcName:    es.SrcName,
+			sandboxKey: es.SandboxKey,
+			ifIndex:    es.IfIndex,
+			limits:     es.Limits,
+			portMap:    es.PortMap,
+			lease:      es.Lease,
+		}
+		if ep.routes, err = parseRoutes(n, strings.Join(es.Routes, ",")); err != nil {
+			return nil, err
+		}
+		if es.Mac != "" {
+			mac, err := net.ParseMAC(es.Mac)
+			if err != nil {
+				return nil, err
+			}
+			ep.mac = mac
+		}
+		if es.Addr != "" {
+			addr, err := netlink.ParseIPNet(es.Addr)
+			if err != nil {
+				return nil, err
+			}
+			ep.addr = addr
+		}
+		if es.AddrV6 != "" {
+			addr, err := netlink.ParseIPNet(es.AddrV6)
+			if err != nil {
+				return nil, err
+			}
+			ep.addrV6 = addr
+		}
+		n.endpoints[ep.id] = ep
+	}
+	return n, nil
+}
+
+// saveState persists the current driver tables. The snapshot is taken
+// under the store lock so an older one never replaces a newer file.
+func (driver *driver) saveState() error {
+	if driver.store == nil {
+		return nil
+	}
+	driver.store.Lock()
+	defer driver.store.Unlock()
+
+	state := &driverState{Version: stateVersion}
+	for _, n := range driver.getNetworks() {
+		state.Networks = append(state.Networks, n.state())
+	}
+	driver.Lock()
+	for name := range driver.ownedLinks {
+		state.OwnedLinks = append(state.OwnedLinks, name)
+	}
+	driver.Unlock()
+	sort.Strings(state.OwnedLinks)
+	state.Pools = driver.ipam.state()
+	return driver.store.save(state)
+}
@@ -0,0 +1,244 @@
+package ipvlan
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/docker/libnetwork/types"
+)
+
+func newTestStore(t *testing.T) (*stateStore, func()) {
+	dir, err := ioutil.TempDir("", "ipvlan-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := newStateStore(filepath.Join(dir, "state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, func() { os.RemoveAll(dir) }
+}
+
+type endpointStatesByID []*endpointState
+
+func (s endpointStatesByID) Len() int           { return len(s) }
+func (s endpointStatesByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s endpointStatesByID) Less(i, j int) bool { return s[i].ID < s[j].ID }
+
+func TestStateStoreLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		networks int
+		wantErr  bool
+	}{
+		{name: "missing file"},
+		{name: "empty state", data: `{"Version":1}`},
+		{name: "networks", data: `{"Version":1,"Networks":[{"ID":"net1"},{"ID":"net2"}]}`, networks: 2},
+		{name: "corrupt", data: `{"Version":1,"Networks":[`, wantErr: true},
+		{name: "empty file", data: " ", wantErr: true},
+		{name: "unknown version", data: `{"Version":2}`, wantErr: true},
+		{name: "version 0", data: `{"Version":0}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		s, cleanup := newTestStore(t)
+		if tt.data != "" {
+			if err := ioutil.WriteFile(s.path, []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		state, err := s.load()
+		cleanup()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: load() error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (state.Version != stateVersion || len(state.Networks) != tt.networks) {
+			t.Errorf("%s: load() = version %d with %d networks, want %d networks", tt.name, state.Version, len(state.Networks), tt.networks)
+		}
+	}
+}
+
+func TestStateStoreSave(t *testing.T) {
+	s, cleanup := newTestStore(t)
+	defer cleanup()
+
+	for _, states := range [][]string{{"net1"}, {"net2", "net3"}, nil} {
+		state := &driverState{Version: stateVersion, OwnedLinks: states}
+		for _, id := range states {
+			state.Networks = append(state.Networks, &networkState{ID: id})
+		}
+		if err := s.save(state); err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, state) {
+			t.Errorf("load() after save() = %+v, want %+v", got, state)
+		}
+		// the state file is replaced, no temporary file is left behind
+		files, _ := ioutil.ReadDir(filepath.Dir(s.path))
+		if len(files) != 1 || files[0].Name() != stateFile {
+			t.Errorf("state directory holds %d files after save()", len(files))
+		}
+		if mode := files[0].Mode().Perm(); mode&0077 != 0 {
+			t.Errorf("state file mode = %v, want it private", mode)
+		}
+	}
+	if err := os.RemoveAll(filepath.Dir(s.path)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.save(&driverState{Version: stateVersion}); err == nil {
+		t.Errorf("save() into a removed directory did not fail")
+	}
+}
+
+func TestNetworkStateRoundTrip(t *testing.T) {
+	_, v4, _ := net.ParseCIDR("192.168.1.0/24")
+	_, v6, _ := net.ParseCIDR("2001:db8::/64")
+	mac, _ := net.ParseMAC("7a:42:c0:a8:01:05")
+	addr := &net.IPNet{IP: net.ParseIP("192.168.1.5").To4(), Mask: v4.Mask}
+	addrV6 := &net.IPNet{IP: net.ParseIP("2001:db8::5"), Mask: v6.Mask}
+	route := func(n *network, spec string) []*route {
+		routes, err := parseRoutes(n, spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return routes
+	}
+
+	l2 := &network{
+		id: "l2", kind: driverKindIpvlan, modeOpt: ipVlanL2, ifaceOpt: "eth1.20", cidr: v4, gateway: "192.168.1.1",
+		cidrV6: v6, gatewayV6: "2001:db8::1", mtu: 1400, txQueueLen: 500, hostShim: true, shimIP: net.ParseIP("192.168.1.250").To4(),
+		bondSlaves: []string{"eth1", "eth2"}, bondMode: "802.3ad", ipvlanFlag: ipvlanFlagBridge, dad: dadWarn,
+		limits: rateLimit{EgressRate: 1e6}, announceCount: 3, announceInterval: time.Second,
+	}
+	l2.routes = route(l2, "10.0.0.0/8 via 192.168.1.254")
+	l2.endpoints = endpointTable{
+		"ep1": {id: "ep1", mac: mac, addr: addr, addrV6: addrV6, srcName: "ipv0123456789ab", sandboxKey: "/var/run/docker/netns/1",
+			ifIndex: 7, limits: rateLimit{IngressRate: 2e6}, routes: route(l2, "172.16.0.0/12")},
+		"ep2": {id: "ep2", addr: &net.IPNet{IP: net.ParseIP("192.168.1.6").To4(), Mask: v4.Mask}},
+	}
+	l3s := &network{
+		id: "l3s", kind: driverKindIpvlan, modeOpt: ipVlanL3S, ifaceOpt: "eth0", cidr: v4, nat: true,
+		snatIP: net.ParseIP("192.0.2.1").To4(), announceCount: 3, announceInterval: time.Second,
+	}
+	l3s.endpoints = endpointTable{
+		"ep1": {id: "ep1", addr: addr, portMap: []types.PortBinding{
+			{Proto: types.TCP, IP: addr.IP, Port: 80, HostIP: net.ParseIP("192.0.2.1").To4(), HostPort: 8080, HostPortEnd: 8080},
+		}},
+	}
+	macvlan := &network{
+		id: "mv", kind: driverKindMacvlan, modeOpt: macvlanBridge, ifaceOpt: "eth0", cidr: v4, dhcp: true,
+		announceCount: 0, announceInterval: 2 * time.Second,
+	}
+	macvlan.endpoints = endpointTable{
+		"ep1": {id: "ep1", mac: mac, addr: addr, lease: &dhcpLease{ClientID: "ep1", Addr: "192.168.1.5/24", Router: "192.168.1.1",
+			Server: "192.168.1.1", ServerMAC: "02:00:00:00:00:01", Obtained: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC),
+			Duration: time.Hour, Renew: 30 * time.Minute}},
+	}
+
+	// the endpoint records follow the map order, compare them sorted
+	encode := func(n *network) []byte {
+		ns := n.state()
+		sort.Sort(endpointStatesByID(ns.Endpoints))
+		data, err := json.Marshal(ns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	for _, n := range []*network{l2, l3s, macvlan} {
+		data := encode(n)
+		var ns networkState
+		if err := json.Unmarshal(data, &ns); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ns.network()
+		if err != nil {
+			t.Errorf("%s: network() error = %v", n.id, err)
+			continue
+		}
+		// the restored network persists the same record
+		if again := encode(got); string(again) != string(data) {
+			t.Errorf("%s: round trip = %s, want %s", n.id, again, data)
+		}
+		for id, ep := range n.endpoints {
+			gotEp := got.endpoint(id)
+			if gotEp == nil || !reflect.DeepEqual(gotEp.lease, ep.lease) || len(gotEp.routes) != len(ep.routes) {
+				t.Errorf("%s: endpoint [ %s ] = %+v, want %+v", n.id, id, gotEp, ep)
+			}
+		}
+	}
+}
+
+func TestNetworkTableSkipsInvalid(t *testing.T) {
+	state := &driverState{Version: stateVersion, Networks: []*networkState{
+		{ID: "good", ModeOpt: ipVlanL2, Cidr: "192.168.1.0/24", Endpoints: []*endpointState{{ID: "ep1", Addr: "192.168.1.5/24"}}},
+		{Cidr: "192.168.2.0/24"},
+		{ID: "bad-cidr", Cidr: "192.168.3.0"},
+		{ID: "bad-mac", Endpoints: []*endpointState{{ID: "ep1", Mac: "7a:42"}}},
+		{ID: "bad-addr", Endpoints: []*endpointState{{ID: "ep1", Addr: "192.168.1.5"}}},
+		{ID: "bad-shim", Cidr: "192.168.4.0/24", HostShim: true},
+		{ID: "bad-route", Cidr: "192.168.5.0/24", Routes: []string{"10.0.0.0/8 via 192.168.6.1"}},
+	}}
+	networks := state.networkTable()
+	if len(networks) != 1 || networks["good"] == nil {
+		t.Fatalf("networkTable() = %v, want only [ good ]", networks)
+	}
+	n := networks["good"]
+	if ep := n.endpoint("ep1"); ep == nil || ep.addr.String() != "192.168.1.5/24" {
+		t.Errorf("endpoint not restored: %+v", ep)
+	}
+	// records written before the announce options default to them
+	if n.announceCount != defaultAnnounceCount || n.announceInterval != defaultAnnounceInterval {
+		t.Errorf("announce = %d %s, want the defaults", n.announceCount, n.announceInterval)
+	}
+}
+
+func TestSaveStateReload(t *testing.T) {
+	d, cleanup := newTestDriver(t, nil)
+	defer cleanup()
+
+	_, v4, _ := net.ParseCIDR("192.168.1.0/24")
+	d.addNetwork(&network{id: "net1", modeOpt: ipVlanL2, ifaceOpt: testParent, cidr: v4, endpoints: endpointTable{
+		"ep1": {id: "ep1", addr: &net.IPNet{IP: net.ParseIP("192.168.1.5").To4(), Mask: v4.Mask}},
+	}})
+	d.ownedLinks["eth1.20"] = true
+	p := testPool(t, "10.0.0.0/24", "", "10.0.0.1")
+	if err := d.ipam.add(p); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ipam.allocate(p.id, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.saveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := d.store.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	networks := state.networkTable()
+	if n := networks["net1"]; n == nil || n.endpoint("ep1") == nil || n.cidr.String() != "192.168.1.0/24" {
+		t.Errorf("networkTable() after reload = %v", networks)
+	}
+	if links := state.ownedLinks(); !links["eth1.20"] || len(links) != 1 {
+		t.Errorf("ownedLinks() after reload = %v", links)
+	}
+	pools := state.poolTable()
+	if got := pools[p.id]; got == nil || !got.allocated["10.0.0.2"] || rangesString(got.excluded) != "10.0.0.1" {
+		t.Errorf("poolTable() after reload = %+v", got)
+	}
+}
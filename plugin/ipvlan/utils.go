@@ -1,38 +1,100 @@
 package ipvlan
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"strings"
+	"syscall"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/vishvananda/netlink"
 )
 
+// makeMac derives a mac from an IPv4 address or the low order 4 bytes of an IPv6 address
 func makeMac(ip net.IP) string {
 	hw := make(net.HardwareAddr, 6)
 	hw[0] = 0x7a
 	hw[1] = 0x42
-	copy(hw[2:], ip.To4())
+	if ip4 := ip.To4(); ip4 != nil {
+		copy(hw[2:], ip4)
+	} else if len(ip) == net.IPv6len {
+		copy(hw[2:], ip[12:])
+	}
 	return hw.String()
 }
 
-// Return the IPv4 address of a network interface
-func getIfaceAddr(name string) (*net.IPNet, error) {
+// linkName returns the default namespace name of an endpoint link for a
+// retry attempt. Names are hashed from the whole endpoint id since id
+// prefixes collide on hosts with thousands of endpoints.
+func linkName(eid string, attempt int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s/%d", eid, attempt)))
+	return linkPrefix + hex.EncodeToString(sum[:])[:maxIfaceNameLen-len(linkPrefix)]
+}
+
+// isLinkName reports whether name follows the linkName scheme of the plugin
+// endpoint links, links created by hand never do
+func isLinkName(name string) bool {
+	if len(name) != maxIfaceNameLen || !strings.HasPrefix(name, linkPrefix) {
+		return false
+	}
+	return strings.Trim(name[len(linkPrefix):], "0123456789abcdef") == ""
+}
+
+// addEndpointLink creates link under the first free name of the endpoint,
+// the chosen name is left in the link attributes
+func addEndpointLink(eid string, link netlink.Link) error {
+	var err error
+	for i := 0; i < linkNameAttempts; i++ {
+		link.Attrs().Name = linkName(eid, i)
+		if _, lookupErr := netlink.LinkByName(link.Attrs().Name); lookupErr == nil {
+			log.Debugf("Link name [ %s ] is taken, retrying", link.Attrs().Name)
+			err = syscall.EEXIST
+			continue
+		}
+		if err = addLink(link); err != syscall.EEXIST {
+			return err
+		}
+	}
+	return fmt.Errorf("no free link name for endpoint [ %s ] after [ %d ] attempts: %s", eid, linkNameAttempts, err)
+}
+
+// Return the address of a network interface for the netlink family
+// (netlink.FAMILY_V4 or netlink.FAMILY_V6). Link local IPv6 addresses are skipped.
+func getIfaceAddr(name string, family int) (*net.IPNet, error) {
 	iface, err := netlink.LinkByName(name)
 	if err != nil {
 		return nil, err
 	}
-	addrs, err := netlink.AddrList(iface, netlink.FAMILY_V4)
+	addrs, err := netlink.AddrList(iface, family)
 	if err != nil {
 		return nil, err
 	}
-	if len(addrs) == 0 {
+	var usable []netlink.Addr
+	for _, addr := range addrs {
+		if !addr.IP.IsLinkLocalUnicast() {
+			usable = append(usable, addr)
+		}
+	}
+	if len(usable) == 0 {
 		return nil, fmt.Errorf("Interface %s has no IP addresses", name)
 	}
-	if len(addrs) > 1 {
-		log.Infof("Interface [ %v ] has more than 1 IPv4 address. Defaulting to using [ %v ]\n", name, addrs[0].IP)
+	if len(usable) > 1 {
+		log.Infof("Interface [ %v ] has more than 1 address. Defaulting to using [ %v ]", name, usable[0].IP)
+	}
+	return usable[0].IPNet, nil
+}
+
+// deleteLink removes a link from the default namespace, a missing link is not an error
+func deleteLink(name string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		log.Debugf("The link [ %s ] was not found on the host, nothing to delete", name)
+		return nil
 	}
-	return addrs[0].IPNet, nil
+	log.Infof("Deleting the ipvlan link [ %s ]", name)
+	return netlink.LinkDel(link)
 }
 
 // Set the IP addr of a link
@@ -70,6 +132,8 @@ func setIpVlanMode(s string) (netlink.IPVlanMode, error) {
 		return netlink.IPVLAN_MODE_L2, nil
 	case "l3", "l3routing":
 		return netlink.IPVLAN_MODE_L3, nil
+	case "l3s":
+		return ipvlanModeL3S, nil
 	default:
 		return 0, fmt.Errorf("unknown ipvlan mode: %q", s)
 	}
@@ -0,0 +1,65 @@
+package ipvlan
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIsLinkName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{linkName("4c9f2a1b7d", 0), true},
+		{linkName("4c9f2a1b7d", 3), true},
+		{"ipv0123456789ab", true},
+		{"ipv0123456789a", false},
+		{"ipv0123456789abc", false},
+		{"ipvlan-uplink01", false},
+		{"ipv0123456789AB", false},
+		{"eth0", false},
+		{shimName("4c9f2a1b7d"), false},
+	}
+	for _, tt := range tests {
+		if got := isLinkName(tt.name); got != tt.want {
+			t.Errorf("isLinkName(%q) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinkName(t *testing.T) {
+	seen := map[string]bool{}
+	for _, eid := range []string{"4c9f2a1b7d", "4c9f2a1b7e", "4c9f2a1b7d0000"} {
+		for attempt := 0; attempt < 4; attempt++ {
+			name := linkName(eid, attempt)
+			if len(name) != maxIfaceNameLen || name[:len(linkPrefix)] != linkPrefix {
+				t.Errorf("linkName(%q, %d) = %q, want %d characters starting with %q", eid, attempt, name, maxIfaceNameLen, linkPrefix)
+			}
+			if name != linkName(eid, attempt) {
+				t.Errorf("linkName(%q, %d) is not stable", eid, attempt)
+			}
+			if seen[name] {
+				t.Errorf("linkName(%q, %d) = %q collides", eid, attempt, name)
+			}
+			seen[name] = true
+		}
+	}
+}
+
+func TestMakeMac(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.168.1.2", "7a:42:c0:a8:01:02"},
+		{"10.0.0.1", "7a:42:0a:00:00:01"},
+		{"::ffff:10.0.0.1", "7a:42:0a:00:00:01"},
+		{"2001:db8::a:b0c:d0e", "7a:42:0b:0c:0d:0e"},
+		{"", "7a:42:00:00:00:00"},
+	}
+	for _, tt := range tests {
+		if got := makeMac(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("makeMac(%q) = %s, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package ipvlan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	minVlanID = 1
+	maxVlanID = 4094
+	// IFNAMSIZ less the trailing NUL
+	maxIfaceNameLen = 15
+	// endpoint links are named linkPrefix and a hash of the endpoint id
+	linkPrefix       = "ipv"
+	linkNameAttempts = 8
+)
+
+// vlanIfaceName returns the 802.1Q sub-interface name for a parent and vlan id
+func vlanIfaceName(parent string, vlanID int) (string, error) {
+	if vlanID < minVlanID || vlanID > maxVlanID {
+		return "", fmt.Errorf("vlan id [ %d ] must be between [ %d ] and [ %d ]", vlanID, minVlanID, maxVlanID)
+	}
+	name := fmt.Sprintf("%s.%d", parent, vlanID)
+	if len(name) > maxIfaceNameLen {
+		return "", fmt.Errorf("sub-interface name [ %s ] exceeds [ %d ] characters", name, maxIfaceNameLen)
+	}
+	return name, nil
+}
+
+// parseVlanIface splits a sub-interface name such as eth1.20 into its parent
+// and vlan id. ok is false when the name does not use the dotted notation.
+func parseVlanIface(name string) (parent string, vlanID int, ok bool) {
+	i := strings.LastIndex(name, ".")
+	if i <= 0 || i == len(name)-1 {
+		return "", 0, false
+	}
+	vlanID, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], vlanID, true
+}
+
+// resolveParentIface returns the parent interface of a network from the
+// host_iface, parent and vlan_id options. A vlan_id selects the 802.1Q
+// sub-interface of parent, or of host_iface when parent is not set.
+func resolveParentIface(hostIface, parent, vlanID string) (string, error) {
+	if parent == "" {
+		// host_iface may already name the sub-interface, e.g. eth1.20
+		if _, vid, ok := parseVlanIface(hostIface); ok && strconv.Itoa(vid) == vlanID {
+			return hostIface, nil
+		}
+		parent = hostIface
+	} else if hostIface != "" && vlanID == "" && hostIface != parent {
+		return "", fmt.Errorf("host_iface [ %s ] and parent [ %s ] conflict", hostIface, parent)
+	}
+	if vlanID == "" {
+		return parent, nil
+	}
+	if parent == "" {
+		parent = ipVlanEthIface
+	}
+	vid, err := strconv.Atoi(vlanID)
+	if err != nil {
+		return "", fmt.Errorf("invalid vlan_id [ %s ]: %s", vlanID, err)
+	}
+	name, err := vlanIfaceName(parent, vid)
+	if err != nil {
+		return "", err
+	}
+	if hostIface != "" && hostIface != parent && hostIface != name {
+		return "", fmt.Errorf("host_iface [ %s ] conflicts with the sub-interface [ %s ] selected by vlan_id", hostIface, name)
+	}
+	return name, nil
+}
+
+// ensureVlanIface creates the 802.1Q sub-interface used as a network parent
+// when it does not exist yet and records it as owned by the plugin.
+func (driver *driver) ensureVlanIface(name, nid string) error {
+	if link, err := netlink.LinkByName(name); err == nil {
+		// existing sub-interfaces are used as is but must be up to pass traffic
+		return netlink.LinkSetUp(link)
+	}
+	parentName, vlanID, ok := parseVlanIface(name)
+	if !ok {
+		return fmt.Errorf("parent interface [ %s ] was not found on the host", name)
+	}
+	if _, err := vlanIfaceName(parentName, vlanID); err != nil {
+		return err
+	}
+	parent, err := netlink.LinkByName(parentName)
+	if err != nil {
+		return fmt.Errorf("parent interface [ %s ] of the sub-interface [ %s ] was not found on the host", parentName, name)
+	}
+	vlan := &netlink.Vlan{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:        name,
+			ParentIndex: parent.Attrs().Index,
+		},
+		VlanId: vlanID,
+	}
+	if err := netlink.LinkAdd(vlan); err != nil {
+		if err == syscall.EEXIST {
+			// created concurrently by someone else, it is not ours to remove
+			return nil
+		}
+		return fmt.Errorf("unable to create the vlan sub-interface [ %s ]: %s", name, err)
+	}
+	log.Infof("Created the 802.1Q sub-interface [ %s ] with vlan id [ %d ] on [ %s ]", name, vlanID, parentName)
+	driver.addOwnedLink(name)
+	if err := netlink.LinkSetUp(vlan); err != nil {
+		driver.releaseOwnedLink(name, nid)
+		return fmt.Errorf("unable to enable the vlan sub-interface [ %s ]: %s", name, err)
+	}
+	// parent must be up for the sub-interface to pass traffic
+	if err := netlink.LinkSetUp(parent); err != nil {
+		log.Warnf("Unable to enable the parent interface [ %s ]: %s", parentName, err)
+	}
+	return nil
+}
+
+func (driver *driver) addOwnedLink(name string) {
+	driver.Lock()
+	driver.ownedLinks[name] = true
+	driver.Unlock()
+}
+
+// releaseOwnedLink deletes a link the plugin created once no network other
+// than the one being removed (nid) uses it as a parent or holds it while
+// being created.
+func (driver *driver) releaseOwnedLink(name, nid string) {
+	driver.Lock()
+	if !driver.ownedLinks[name] {
+		driver.Unlock()
+		return
+	}
+	for holder := range driver.linkHolds[name] {
+		if holder != nid {
+			driver.Unlock()
+			log.Debugf("Keeping the sub-interface [ %s ] held by the network [ %s ] being created", name, holder)
+			return
+		}
+	}
+	for _, n := range driver.networks {
+		if n.id != nid && (n.ifaceOpt == name || n.bondIface() == name) {
+			driver.Unlock()
+			log.Debugf("Keeping the sub-interface [ %s ] still used by network [ %s ]", name, n.id)
+			return
+		}
+	}
+	delete(driver.ownedLinks, name)
+	driver.Unlock()
+
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		log.Debugf("The plugin created link [ %s ] is already gone", name)
+		return
+	}
+	log.Infof("Deleting the plugin created link [ %s ]", name)
+	if err := netlink.LinkDel(link); err != nil {
+		log.Errorf("Unable to delete the plugin created link [ %s ]: %s", name, err)
+	}
+}
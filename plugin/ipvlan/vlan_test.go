@@ -0,0 +1,57 @@
+package ipvlan
+
+import "testing"
+
+func TestReleaseOwnedLinkHeld(t *testing.T) {
+	// the sub-interface does not exist, only the ownership records change
+	const name = "ipvtest0.20"
+	d := &driver{networks: networkTable{}, ownedLinks: map[string]bool{name: true}}
+	a := &network{id: "a", ifaceOpt: name}
+	b := &network{id: "b", ifaceOpt: name}
+	d.holdParentLinks(a)
+	d.holdParentLinks(b)
+
+	// a failed create of a must not delete the link b is being created on
+	d.releaseParentLinks(a)
+	if !d.ownedLinks[name] {
+		t.Fatalf("the sub-interface held by network b was released")
+	}
+	d.addNetwork(b)
+	if len(d.linkHolds) != 0 {
+		t.Errorf("holds left after the network was added: %v", d.linkHolds)
+	}
+	d.releaseOwnedLink(name, "c")
+	if !d.ownedLinks[name] {
+		t.Fatalf("the sub-interface used by network b was released")
+	}
+	d.delNetwork(b.id)
+	d.releaseParentLinks(b)
+	if d.ownedLinks[name] {
+		t.Errorf("the unused sub-interface was not released")
+	}
+}
+
+func TestResolveParentIface(t *testing.T) {
+	tests := []struct {
+		hostIface, parent, vlanID string
+		want                      string
+		wantErr                   bool
+	}{
+		{"eth1", "", "", "eth1", false},
+		{"eth1", "", "20", "eth1.20", false},
+		{"eth1.20", "", "20", "eth1.20", false},
+		{"", "eth2", "30", "eth2.30", false},
+		{"eth2.30", "eth2", "30", "eth2.30", false},
+		{"eth1", "eth2", "", "", true},
+		{"eth1", "eth2", "30", "", true},
+		{"eth1", "", "4095", "", true},
+		{"eth1", "", "abc", "", true},
+		{"verylongiface0", "", "100", "", true},
+	}
+	for _, tt := range tests {
+		got, err := resolveParentIface(tt.hostIface, tt.parent, tt.vlanID)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("resolveParentIface(%q, %q, %q) = %q, %v, want %q", tt.hostIface, tt.parent, tt.vlanID, got, err, tt.want)
+		}
+	}
+}
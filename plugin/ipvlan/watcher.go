@@ -0,0 +1,115 @@
+package ipvlan
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/gopher-net/ipvlan-docker-plugin/plugin/routing"
+	"github.com/vishvananda/netlink"
+)
+
+const watchRetryInterval = 5 * time.Second
+
+// watchParents follows the host link updates and degrades the networks
+// whose parent interface goes down, loses its carrier, is renamed or
+// disappears. The subscription is renewed if the netlink socket fails.
+func (driver *driver) watchParents() {
+	for {
+		updates := make(chan netlink.LinkUpdate)
+		if err := netlink.LinkSubscribe(updates, nil); err != nil {
+			log.Errorf("Unable to subscribe to the host link updates: %s", err)
+			time.Sleep(watchRetryInterval)
+			continue
+		}
+		// catch up with the changes made while not subscribed
+		driver.checkParents()
+		for update := range updates {
+			attrs := update.Link.Attrs()
+			for _, n := range driver.getNetworks() {
+				n.Lock()
+				affected := n.ifaceOpt == attrs.Name || n.parentIndex == attrs.Index
+				n.Unlock()
+				if affected {
+					driver.checkParent(n)
+				}
+			}
+		}
+		log.Warnf("The host link update subscription closed, resubscribing")
+		time.Sleep(watchRetryInterval)
+	}
+}
+
+// checkParents re-evaluates the parent interface of every network
+func (driver *driver) checkParents() {
+	for _, n := range driver.getNetworks() {
+		driver.checkParent(n)
+	}
+}
+
+// checkParent degrades or restores a network from the state of its parent
+func (driver *driver) checkParent(n *network) {
+	index, reason := parentHealth(n.ifaceOpt)
+	n.Lock()
+	if index > 0 {
+		n.parentIndex = index
+	}
+	was := n.degraded
+	n.degraded = reason
+	n.Unlock()
+
+	switch {
+	case was == "" && reason != "":
+		log.Warnf("Network [ %s ] is degraded: parent interface [ %s ] %s", n.id, n.ifaceOpt, reason)
+		if n.mode() == ipVlanL3Routing && n.cidr != nil {
+			if err := routing.WithdrawRoute(n.cidr); err != nil {
+				log.Errorf("Unable to withdraw the prefix [ %s ] of network [ %s ]: %s", n.cidr, n.id, err)
+			}
+		}
+	case was != "" && reason == "":
+		n.hostLock.Lock()
+		defer n.hostLock.Unlock()
+		if current, err := driver.getNetwork(n.id); err != nil || current != n || n.deleting {
+			log.Debugf("Not restoring network [ %s ], it is being deleted", n.id)
+			return
+		}
+		log.Infof("Network [ %s ] recovered, parent interface [ %s ] is up", n.id, n.ifaceOpt)
+		// routes through a downed interface are flushed by the kernel
+		if err := driver.restoreNetworkRoutes(n); err != nil {
+			log.Errorf("Unable to restore the routes for network [ %s ]: %s", n.id, err)
+		}
+		if err := ensureHostShim(n); err != nil {
+			log.Errorf("Unable to restore the host shim for network [ %s ]: %s", n.id, err)
+		}
+	}
+}
+
+// parentHealth returns the index of a parent interface and why it cannot
+// carry traffic, an empty reason means it is up with a carrier
+func parentHealth(name string) (int, string) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return 0, "is missing"
+	}
+	index := link.Attrs().Index
+	if link.Attrs().Flags&net.FlagUp == 0 {
+		return index, "is down"
+	}
+	// the vendored netlink does not expose the operational state
+	carrier, err := ioutil.ReadFile(fmt.Sprintf("/sys/class/net/%s/carrier", name))
+	if err == nil && strings.TrimSpace(string(carrier)) == "0" {
+		return index, "has no carrier"
+	}
+	return index, ""
+}
+
+// degradedReason returns why the network cannot accept joins, if it cannot
+func (n *network) degradedReason() string {
+	n.Lock()
+	defer n.Unlock()
+
+	return n.degraded
+}
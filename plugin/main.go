@@ -36,8 +36,16 @@ func main() {
 		ipvlan.FlagIpvlanEthIface,
 		ipvlan.FlagIPVlanMode,
 		ipvlan.FlagMtu,
+		ipvlan.FlagTxQueueLen,
 		ipvlan.FlagRoutingManager,
 		ipvlan.FlagBgpAs,
+		ipvlan.FlagStateDir,
+		ipvlan.FlagGcInterval,
+		ipvlan.FlagGcDelete,
+		ipvlan.FlagMacvlanSocket,
+	}
+	app.Commands = []cli.Command{
+		ipvlan.CmdOptions,
 	}
 	app.Before = initEnv
 	app.Action = Run
@@ -45,7 +53,6 @@ func main() {
 }
 
 func initEnv(ctx *cli.Context) error {
-	socketFile := ctx.String("socket")
 	// Default loglevel is Info
 	if ctx.Bool("debug") {
 		log.SetLevel(log.DebugLevel)
@@ -53,12 +60,16 @@ func initEnv(ctx *cli.Context) error {
 		log.SetLevel(log.InfoLevel)
 	}
 	log.SetOutput(os.Stderr)
-	initSock(socketFile)
 	return nil
 }
 
 // Run initializes the driver
 func Run(ctx *cli.Context) {
+	// the sockets are set up here so subcommands leave a running plugin alone
+	initSock(ctx.String("socket"))
+	if macvlanSocket := ctx.String("macvlan-socket"); macvlanSocket != "" {
+		initSock(macvlanSocket)
+	}
 	var d ipvlan.Driver
 	var err error
 	if d, err = ipvlan.New(version, ctx); err != nil {
@@ -66,6 +77,14 @@ func Run(ctx *cli.Context) {
 	}
 	log.Info("IPVlan network driver initialized successfully")
 
+	if macvlanSocket := ctx.String("macvlan-socket"); macvlanSocket != "" {
+		go func() {
+			if err := d.ListenMacvlan(fmt.Sprint(pluginPath, macvlanSocket)); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		log.Infof("Macvlan network driver listening on [ %s ]", macvlanSocket)
+	}
 	// concatenate the absolute path to the spec file handle
 	absSocket := fmt.Sprint(pluginPath, ctx.String("socket"))
 	if err := d.Listen(absSocket); err != nil {
@@ -32,7 +32,6 @@ func cleanExistingRoutes(ifaceStr string) error {
 			log.Debugf("Ignoring route [ %v ] Dst is nil", route)
 			continue
 		}
-		fmt.Printf(route.Src.String())
 		if netOverlaps(ifaceIP, route.Dst) == true {
 			log.Warnf("Ignoring route [ %v ] as it is associated to the [ %s ] interface", ifaceIP, ifaceStr)
 		} else if route.Scope == 0x0 || route.Scope == 0xfd {
@@ -97,7 +96,7 @@ func getIfaceIP(name string) (*net.IPNet, error) {
 		return nil, fmt.Errorf("Interface %v has no IP addresses", name)
 	}
 	if len(addrs) > 1 {
-		log.Info("Interface %v has more than 1 IPv4 address. Default is %v\n", name, addrs[0].IP)
+		log.Infof("Interface %v has more than 1 IPv4 address. Default is %v", name, addrs[0].IP)
 	}
 	return addrs[0].IPNet, nil
 }
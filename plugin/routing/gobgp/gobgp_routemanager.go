@@ -120,7 +120,7 @@ func (b *BgpRouteManager) StartMonitoring() error {
 					}
 				}
 			}
-			log.Debugf("Verbose update details: %s", monitorUpdate)
+			log.Debugf("Verbose update details: %s", monitorUpdate.stringer())
 
 		case arg := <-b.ModPeerCh:
 			_, err := b.bgpgrpcclient.ModNeighbor(context.Background(), arg)
@@ -322,7 +322,7 @@ func (cache *RibCache) handleBgpRibMonitor(routeMonitor *api.Path) (*RibLocal, e
 		case bgp.BGP_ATTR_TYPE_ORIGIN:
 			// 0 = iBGP; 1 = eBGP
 			if p.(*bgp.PathAttributeOrigin).Value != nil {
-				log.Debugf("Type Code: [ %d ] Origin: %g", bgp.BGP_ATTR_TYPE_ORIGIN, p.(*bgp.PathAttributeOrigin).String())
+				log.Debugf("Type Code: [ %d ] Origin: %s", bgp.BGP_ATTR_TYPE_ORIGIN, p.(*bgp.PathAttributeOrigin).String())
 			}
 		case bgp.BGP_ATTR_TYPE_AS_PATH:
 			if p.(*bgp.PathAttributeAsPath).Value != nil {
@@ -339,11 +339,11 @@ func (cache *RibCache) handleBgpRibMonitor(routeMonitor *api.Path) (*RibLocal, e
 			}
 		case bgp.BGP_ATTR_TYPE_MULTI_EXIT_DISC:
 			if p.(*bgp.PathAttributeMultiExitDisc).Value >= 0 {
-				log.Debugf("Type Code: [ %d ] MED: %g", bgp.BGP_ATTR_TYPE_MULTI_EXIT_DISC, p.String())
+				log.Debugf("Type Code: [ %d ] MED: %s", bgp.BGP_ATTR_TYPE_MULTI_EXIT_DISC, p.String())
 			}
 		case bgp.BGP_ATTR_TYPE_LOCAL_PREF:
 			if p.(*bgp.PathAttributeLocalPref).Value >= 0 {
-				log.Debugf("Type Code: [ %d ] Local Pref: %g", bgp.BGP_ATTR_TYPE_LOCAL_PREF, p.String())
+				log.Debugf("Type Code: [ %d ] Local Pref: %s", bgp.BGP_ATTR_TYPE_LOCAL_PREF, p.String())
 			}
 		case bgp.BGP_ATTR_TYPE_ORIGINATOR_ID:
 			if p.(*bgp.PathAttributeOriginatorId).Value != nil {
@@ -377,7 +377,7 @@ func (cache *RibCache) handleBgpRibMonitor(routeMonitor *api.Path) (*RibLocal, e
 				log.Debugf("Type Code: [ %d ] Extended Communities: %v", bgp.BGP_ATTR_TYPE_EXTENDED_COMMUNITIES, p.String())
 			}
 		default:
-			log.Errorf("Unknown BGP attribute code [ %d ]")
+			log.Errorf("Unknown BGP attribute code [ %d ]", p.GetType())
 		}
 	}
 	return ribLocal, nil
@@ -1,12 +1,23 @@
 package routing
 
 import (
+	"errors"
 	log "github.com/Sirupsen/logrus"
 	"github.com/gopher-net/ipvlan-docker-plugin/plugin/routing/gobgp"
 	"net"
+	"sync"
 )
 
-var routemanager RoutingInterface
+var (
+	routemanager RoutingInterface
+	// guards routemanager, it is set by the monitoring goroutine while the
+	// driver handlers already run
+	routemanagerMu sync.RWMutex
+)
+
+// ErrNotInitialized is returned when a route is modified before a routing
+// manager has been started with InitRoutingMonitering
+var ErrNotInitialized = errors.New("routing manager is not initialized, start the plugin with --mode=l3routing")
 
 type Host struct {
 	isself  bool
@@ -22,42 +33,69 @@ type RoutingInterface interface {
 }
 
 func InitRoutingMonitering(masterIface string, managermode string, as string) {
+	var rm RoutingInterface
 	switch managermode {
 	case "gobgp":
 		log.Infof("Routing manager is %s", managermode)
-		routemanager = gobgp.NewBgpRouteManager(masterIface, as)
+		rm = gobgp.NewBgpRouteManager(masterIface, as)
 	default:
 		log.Infof("Default Routing manager: Gobgp")
-		routemanager = gobgp.NewBgpRouteManager(masterIface, as)
+		rm = gobgp.NewBgpRouteManager(masterIface, as)
 	}
-	error := routemanager.StartMonitoring()
+	routemanagerMu.Lock()
+	routemanager = rm
+	routemanagerMu.Unlock()
+	error := rm.StartMonitoring()
 	if error != nil {
 		log.Fatal(error)
 	}
 }
-func withdrawRoute(localPrefix *net.IPNet) error {
-	error := routemanager.WithdrawRoute(localPrefix)
+
+// manager returns the running routing manager, nil before it is started
+func manager() RoutingInterface {
+	routemanagerMu.RLock()
+	defer routemanagerMu.RUnlock()
+	return routemanager
+}
+func WithdrawRoute(localPrefix *net.IPNet) error {
+	rm := manager()
+	if rm == nil {
+		return ErrNotInitialized
+	}
+	error := rm.WithdrawRoute(localPrefix)
 	if error != nil {
 		return error
 	}
 	return nil
 }
 func AdvertizeNewRoute(localPrefix *net.IPNet) error {
-	error := routemanager.AdvertizeNewRoute(localPrefix)
+	rm := manager()
+	if rm == nil {
+		return ErrNotInitialized
+	}
+	error := rm.AdvertizeNewRoute(localPrefix)
 	if error != nil {
 		return error
 	}
 	return nil
 }
 func DiscoverNew(isself bool, Address string) error {
-	error := routemanager.DiscoverNew(isself, Address)
+	rm := manager()
+	if rm == nil {
+		return ErrNotInitialized
+	}
+	error := rm.DiscoverNew(isself, Address)
 	if error != nil {
 		return error
 	}
 	return nil
 }
 func DiscoverDelete(isself bool, Address string) error {
-	error := routemanager.DiscoverDelete(isself, Address)
+	rm := manager()
+	if rm == nil {
+		return ErrNotInitialized
+	}
+	error := rm.DiscoverDelete(isself, Address)
 	if error != nil {
 		return error
 	}